@@ -0,0 +1,76 @@
+package main
+
+import (
+	"api-gateway/services/cms"
+	"errors"
+	"log"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/utils"
+)
+
+// cmsErrorStatus maps a CMS error kind to the status and code returned to clients
+var cmsErrorStatus = []struct {
+	kind   error
+	status int
+	code   string
+}{
+	{cms.ErrNotFound, fiber.StatusNotFound, "NOT_FOUND"},
+	{cms.ErrBadInput, fiber.StatusBadRequest, "BAD_INPUT"},
+	{cms.ErrForbidden, fiber.StatusForbidden, "FORBIDDEN"},
+	{cms.ErrTimeout, fiber.StatusGatewayTimeout, "UPSTREAM_TIMEOUT"},
+	{cms.ErrUnavailable, fiber.StatusServiceUnavailable, "UPSTREAM_UNAVAILABLE"},
+	{cms.ErrValidation, fiber.StatusBadGateway, "UPSTREAM_QUERY_INVALID"},
+	{cms.ErrDecode, fiber.StatusBadGateway, "UPSTREAM_DECODE_FAILED"},
+	{cms.ErrUpstream, fiber.StatusBadGateway, "UPSTREAM_ERROR"},
+	{cms.ErrCache, fiber.StatusServiceUnavailable, "CACHE_UNAVAILABLE"},
+}
+
+// errorHandler writes every error returned by a handler as
+// {"error": message, "code": CODE, "detail": detail, "requestId": id}
+func errorHandler(c *fiber.Ctx, err error) error {
+	status := fiber.StatusInternalServerError
+	code := "INTERNAL_ERROR"
+	message := "Internal server error"
+	detail := ""
+	logged := false
+
+	var cmsErr *cms.Error
+	var fiberErr *fiber.Error
+	switch {
+	case errors.As(err, &cmsErr):
+		for _, mapping := range cmsErrorStatus {
+			if errors.Is(cmsErr, mapping.kind) {
+				status, code = mapping.status, mapping.code
+				break
+			}
+		}
+		// Clients get a fixed message per kind and the gateway's own detail; upstream
+		// details (Strapi's error text) are logged, not returned
+		message = cmsErr.Kind.Error()
+		if status < fiber.StatusInternalServerError {
+			detail = cmsErr.Detail
+		}
+		logged = cmsErr.Detail == ""
+	case errors.As(err, &fiberErr):
+		status = fiberErr.Code
+		code = strings.ToUpper(strings.ReplaceAll(utils.StatusMessage(status), " ", "_"))
+		message = fiberErr.Message
+	}
+
+	requestID, _ := c.Locals("requestid").(string)
+	if logged || status >= fiber.StatusInternalServerError {
+		log.Printf("[%s] %s %s failed: %v", requestID, c.Method(), c.Path(), err)
+	}
+
+	body := fiber.Map{
+		"error":     message,
+		"code":      code,
+		"requestId": requestID,
+	}
+	if detail != "" {
+		body["detail"] = detail
+	}
+	return c.Status(status).JSON(body)
+}
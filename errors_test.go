@@ -0,0 +1,144 @@
+package main
+
+import (
+	"api-gateway/services/cms"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/requestid"
+)
+
+func TestErrorHandler(t *testing.T) {
+	tests := []struct {
+		name       string
+		err        error
+		wantStatus int
+		wantBody   map[string]string
+	}{
+		{
+			"not found with detail",
+			&cms.Error{Kind: cms.ErrNotFound, Detail: "brand not found"},
+			404,
+			map[string]string{"error": "not found", "code": "NOT_FOUND", "detail": "brand not found"},
+		},
+		{
+			"bad input with detail",
+			&cms.Error{Kind: cms.ErrBadInput, Detail: "limit must be an integer between 1 and 50"},
+			400,
+			map[string]string{"error": "invalid input", "code": "BAD_INPUT", "detail": "limit must be an integer between 1 and 50"},
+		},
+		{
+			"upstream message is not returned",
+			&cms.Error{Kind: cms.ErrNotFound, Message: "GraphQL errors: entity car-variant with id 42 not found in table cv_5"},
+			404,
+			map[string]string{"error": "not found", "code": "NOT_FOUND"},
+		},
+		{
+			"forbidden",
+			&cms.Error{Kind: cms.ErrForbidden, Message: "CMS responded with status 401"},
+			403,
+			map[string]string{"error": "access to CMS content forbidden", "code": "FORBIDDEN"},
+		},
+		{
+			"timeout",
+			&cms.Error{Kind: cms.ErrTimeout, Err: errors.New("dial tcp 10.0.0.5:1337: i/o timeout")},
+			504,
+			map[string]string{"error": "CMS request timed out", "code": "UPSTREAM_TIMEOUT"},
+		},
+		{
+			"unavailable",
+			&cms.Error{Kind: cms.ErrUnavailable, Message: "CMS circuit breaker open"},
+			503,
+			map[string]string{"error": "CMS unavailable", "code": "UPSTREAM_UNAVAILABLE"},
+		},
+		{
+			"validation",
+			&cms.Error{Kind: cms.ErrValidation, Message: "GraphQL errors: Cannot query field \"foo\""},
+			502,
+			map[string]string{"error": "GraphQL query failed validation", "code": "UPSTREAM_QUERY_INVALID"},
+		},
+		{
+			"decode",
+			&cms.Error{Kind: cms.ErrDecode, Message: "failed to unmarshal brands"},
+			502,
+			map[string]string{"error": "failed to decode CMS response", "code": "UPSTREAM_DECODE_FAILED"},
+		},
+		{
+			"upstream",
+			&cms.Error{Kind: cms.ErrUpstream, Message: "CMS responded with status 500"},
+			502,
+			map[string]string{"error": "CMS returned an error", "code": "UPSTREAM_ERROR"},
+		},
+		{
+			"cache",
+			&cms.Error{Kind: cms.ErrCache, Message: "cache invalidation failed", Err: errors.New("dial tcp redis:6379: connection refused")},
+			503,
+			map[string]string{"error": "cache operation failed", "code": "CACHE_UNAVAILABLE"},
+		},
+		{
+			"detail of a server error is not returned",
+			&cms.Error{Kind: cms.ErrUnavailable, Detail: "internal"},
+			503,
+			map[string]string{"error": "CMS unavailable", "code": "UPSTREAM_UNAVAILABLE"},
+		},
+		{
+			"wrapped",
+			fmt.Errorf("failed to get brands: %w", &cms.Error{Kind: cms.ErrNotFound, Detail: "brand not found"}),
+			404,
+			map[string]string{"error": "not found", "code": "NOT_FOUND", "detail": "brand not found"},
+		},
+		{
+			"fiber error",
+			fiber.NewError(fiber.StatusUnauthorized, "Unauthorized: Invalid or missing secret key"),
+			401,
+			map[string]string{"error": "Unauthorized: Invalid or missing secret key", "code": "UNAUTHORIZED"},
+		},
+		{
+			"fiber error with a multi-word status",
+			fiber.NewError(fiber.StatusRequestEntityTooLarge, "Body too large"),
+			413,
+			map[string]string{"error": "Body too large", "code": "REQUEST_ENTITY_TOO_LARGE"},
+		},
+		{
+			"other errors are hidden",
+			errors.New("pq: password authentication failed for user admin"),
+			500,
+			map[string]string{"error": "Internal server error", "code": "INTERNAL_ERROR"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			app := fiber.New(fiber.Config{ErrorHandler: errorHandler})
+			app.Use(requestid.New(requestid.Config{Generator: func() string { return "req-1" }}))
+			app.Get("/", func(c *fiber.Ctx) error { return test.err })
+
+			resp, err := app.Test(httptest.NewRequest("GET", "/", nil))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if resp.StatusCode != test.wantStatus {
+				t.Errorf("status = %d, want %d", resp.StatusCode, test.wantStatus)
+			}
+
+			raw, _ := io.ReadAll(resp.Body)
+			var body map[string]string
+			if err := json.Unmarshal(raw, &body); err != nil {
+				t.Fatalf("body %s: %v", raw, err)
+			}
+			want := map[string]string{"requestId": "req-1"}
+			for key, value := range test.wantBody {
+				want[key] = value
+			}
+			if !reflect.DeepEqual(body, want) {
+				t.Errorf("body = %v, want %v", body, want)
+			}
+		})
+	}
+}
@@ -4,9 +4,13 @@ import (
 	"api-gateway/pkg/cache"
 	"api-gateway/pkg/locale"
 	"api-gateway/services/cms"
+	"api-gateway/services/cms/media"
+	"api-gateway/services/cms/pricing"
 	"context"
 	"log"
 	"os"
+	"path/filepath"
+	"strconv"
 	"strings"
 	"time"
 
@@ -15,12 +19,17 @@ import (
 	"github.com/gofiber/fiber/v2/middleware/logger"
 	"github.com/gofiber/fiber/v2/middleware/proxy"
 	"github.com/gofiber/fiber/v2/middleware/recover"
+	"github.com/gofiber/fiber/v2/middleware/requestid"
 	"github.com/joho/godotenv"
-	"github.com/redis/go-redis/v9"
 	"github.com/goccy/go-json"
 )
 
 func main() {
+	// validate-queries [schema.gql] checks the CMS operations against the schema and exits
+	if len(os.Args) > 1 && os.Args[1] == "validate-queries" {
+		os.Exit(validateQueries(os.Args[2:]))
+	}
+
 	// Load .env file
 	if err := godotenv.Load(); err != nil {
 		log.Println("No .env file found or error loading it")
@@ -31,9 +40,11 @@ func main() {
 		AppName: "API Gateway",
 		JSONEncoder: json.Marshal,
 		JSONDecoder: json.Unmarshal,
+		ErrorHandler: errorHandler,
 	})
 
 	// Middleware
+	app.Use(requestid.New())
 	app.Use(recover.New())
 	app.Use(logger.New())
 	app.Use(cors.New())
@@ -52,12 +63,16 @@ func main() {
 		return c.Next()
 	})
 
-	// Health check route
-	app.Get("/health", func(c *fiber.Ctx) error {
-		return c.JSON(fiber.Map{
-			"status":  "ok",
-			"service": "api-gateway",
-		})
+	// Stale marker - flags responses built from CMS cache entries past their soft TTL
+	app.Use(func(c *fiber.Ctx) error {
+		ctx, staleTracker := cms.WithStaleTracker(c.UserContext())
+		c.SetUserContext(ctx)
+
+		err := c.Next()
+		if staleTracker.Stale() {
+			c.Set("X-Cache-Status", "STALE")
+		}
+		return err
 	})
 
 	// API Documentation
@@ -70,31 +85,29 @@ func main() {
 	})
 
 	// Initialize Redis client for caching
-	redisAddr := os.Getenv("REDIS_ADDR")
-	if redisAddr == "" {
-		redisAddr = "localhost:6379"
+	// REDIS_MODE selects standalone (default), sentinel or cluster. REDIS_ADDRS is a
+	// comma-separated list of Sentinel or cluster seed addresses; REDIS_ADDR is kept for standalone.
+	redisAddrs := os.Getenv("REDIS_ADDRS")
+	if redisAddrs == "" {
+		redisAddrs = os.Getenv("REDIS_ADDR")
+	}
+	if redisAddrs == "" {
+		redisAddrs = "localhost:6379"
 	}
-	redisPassword := os.Getenv("REDIS_PASSWORD")
-	redisDB := 0 // Use DB 0 for CMS cache
 
-	redisClient := redis.NewClient(&redis.Options{
-		Addr:            redisAddr,
-		Password:        redisPassword,
-		DB:              redisDB,
+	redisClient, err := cache.NewRedisClient(cache.RedisConfig{
+		Mode:             os.Getenv("REDIS_MODE"),
+		Addrs:            strings.Split(redisAddrs, ","),
+		Password:         os.Getenv("REDIS_PASSWORD"),
+		DB:               0, // Use DB 0 for CMS cache
+		MasterName:       os.Getenv("REDIS_MASTER_NAME"),
+		SentinelPassword: os.Getenv("REDIS_SENTINEL_PASSWORD"),
 	})
-
-	// Test Redis connection
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-	defer cancel()
-
-	if err := redisClient.Ping(ctx).Err(); err != nil {
-		log.Printf("Warning: Redis connection failed: %v. Running without cache.", err)
-		redisClient = nil // Disable cache if Redis is unavailable
-	} else {
-		log.Println("Redis connection established")
+	if err != nil {
+		log.Fatalf("Invalid Redis configuration: %v", err)
 	}
 
-	// Initialize CMS client with Redis cache
+	// Initialize CMS client
 	cmsServiceURL := os.Getenv("CMS_SERVICE_URL")
 	if cmsServiceURL == "" {
 		cmsServiceURL = "http://localhost:1337/graphql"
@@ -106,25 +119,149 @@ func main() {
 		cmsMediaBaseURL = "http://localhost:1337"
 	}
 
+	// Media URLs point at CMS_MEDIA_BASE_URL, or at a CDN with optional path mapping, cache-busting
+	// versions and expiring signatures for private files (verified below when serving /uploads)
+	mediaStrategy := &media.URLStrategy{
+		BaseURL:   cmsMediaBaseURL,
+		PathMap:   media.ParsePathMap(os.Getenv("CMS_MEDIA_PATH_MAP")),
+		CacheBust: os.Getenv("CMS_MEDIA_CACHE_BUST") == "true",
+	}
+	if cdnURL := os.Getenv("CMS_MEDIA_CDN_URL"); cdnURL != "" {
+		mediaStrategy.BaseURL = cdnURL
+	}
+	if signingKey := os.Getenv("CMS_MEDIA_SIGNING_KEY"); signingKey != "" {
+		signedTTL, _ := time.ParseDuration(os.Getenv("CMS_MEDIA_SIGNED_TTL"))
+		mediaStrategy.Signer = media.NewSigner([]byte(signingKey), signedTTL)
+		if extensions := os.Getenv("CMS_MEDIA_SIGNED_EXTENSIONS"); extensions != "" {
+			mediaStrategy.SignedExtensions = strings.Split(extensions, ",")
+		}
+	}
+
+	// Fail fast on queries that Strapi would reject
+	if err := cms.ValidateOperations(cmsSchema); err != nil {
+		log.Fatalf("CMS operations do not match schema.gql: %v", err)
+	}
+
 	// Debug: Check if token is loaded
 	if cmsServiceToken == "" {
 		log.Println("WARNING: CMS_SERVICE_TOKEN is not set!")
 	}
 
-	var cmsCache cache.Cache
-	if redisClient != nil {
-		cmsCache = cache.NewRedisCache(redisClient, "cms:")
-	} else {
-		cmsCache = &cache.NoOpCache{} // Fallback to no-op cache
+	// In-process cache in front of Redis for hot keys
+	localCacheSize, _ := strconv.Atoi(os.Getenv("CMS_LOCAL_CACHE_SIZE"))
+	if localCacheSize == 0 {
+		localCacheSize = 1000
+	}
+	localCacheMaxBytes, _ := strconv.ParseInt(os.Getenv("CMS_LOCAL_CACHE_MAX_BYTES"), 10, 64)
+	if localCacheMaxBytes == 0 {
+		localCacheMaxBytes = 64 << 20 // 64MB
+	}
+	localCacheTTL, _ := time.ParseDuration(os.Getenv("CMS_LOCAL_CACHE_TTL"))
+	if localCacheTTL == 0 {
+		localCacheTTL = 1 * time.Minute
+	}
+	localCache := cache.NewMemoryCache(cache.MemoryCacheConfig{
+		MaxEntries: localCacheSize,
+		MaxBytes:   localCacheMaxBytes,
+		MaxTTL:     localCacheTTL,
+	})
+
+	// Broadcast invalidations so every replica evicts its local copies
+	invalidationBus := cache.NewInvalidationBus(redisClient, "cms:invalidations")
+	go invalidationBus.Subscribe(context.Background(), localCache)
+
+	// Optional zstd compression of values stored in Redis (the local cache keeps them uncompressed).
+	// Enable only once every replica can decode compressed entries.
+	redisStore := cache.NewRedisCache(redisClient, "cms:")
+	var redisCache cache.Cache = redisStore
+	var compressedCache *cache.CompressedCache
+	if os.Getenv("CMS_CACHE_COMPRESSION") == "zstd" {
+		compressionThreshold, _ := strconv.Atoi(os.Getenv("CMS_CACHE_COMPRESSION_THRESHOLD"))
+		compressedCache = cache.NewCompressedCache(redisCache, cache.CompressedCacheConfig{
+			Threshold: compressionThreshold,
+		})
+		redisCache = compressedCache
+	}
+
+	// Redis may be unavailable at startup or go away later: the resilient cache serves from
+	// the local cache until Redis answers pings, and trips back to it on repeated errors
+	cmsCache := cache.NewResilientCache(
+		cache.NewTieredCache(localCache, redisCache, invalidationBus),
+		localCache,
+		func(ctx context.Context) error { return redisClient.Ping(ctx).Err() },
+		// Lost invalidations only concern CMS responses, not media placeholders
+		cache.ResilientCacheConfig{OverflowPattern: "cms:graphql:*"},
+	)
+	go cmsCache.Run(context.Background())
+
+	// Remove expired keys from the tag index sets, which broad tags would otherwise grow forever
+	tagPruneInterval, _ := time.ParseDuration(os.Getenv("CMS_TAG_PRUNE_INTERVAL"))
+	if tagPruneInterval == 0 {
+		tagPruneInterval = 10 * time.Minute
 	}
+	go redisStore.RunTagIndexPruning(context.Background(), tagPruneInterval)
+
+	// Per-operation cache policies, e.g. {"AppVersion": {"ttl": "30s", "stale": "5m"}}
+	var cmsCachePolicies map[string]cms.CachePolicy
+	if rawPolicies := os.Getenv("CMS_CACHE_POLICIES"); rawPolicies != "" {
+		policies, err := cms.ParseCachePolicies(rawPolicies)
+		if err != nil {
+			log.Printf("Warning: ignoring CMS_CACHE_POLICIES: %v", err)
+		} else {
+			cmsCachePolicies = policies
+		}
+	}
+
+	// Market price rules, e.g. [{"brands": ["mercedes"], "method": "percent", "value": 12}, {"method": "showroom-median"}]
+	var pricingRules pricing.Rules
+	if rawRules := os.Getenv("CMS_PRICING_RULES"); rawRules != "" {
+		rules, err := pricing.ParseRules(rawRules)
+		if err != nil {
+			log.Printf("Warning: ignoring CMS_PRICING_RULES: %v", err)
+		} else {
+			pricingRules = rules
+		}
+	}
+
+	// Retries of transient CMS failures (-1 disables) and the circuit breaker that fails fast,
+	// serving stale cache entries, while the CMS is down
+	cmsMaxRetries, _ := strconv.Atoi(os.Getenv("CMS_MAX_RETRIES"))
+	cmsBreakerThreshold, _ := strconv.Atoi(os.Getenv("CMS_BREAKER_THRESHOLD"))
+	cmsBreakerOpenDuration, _ := time.ParseDuration(os.Getenv("CMS_BREAKER_OPEN_DURATION"))
+
+	// Registered operations are sent by name and hash; set CMS_PERSISTED_QUERIES=false
+	// to always send full queries
+	cmsPersistedQueries := os.Getenv("CMS_PERSISTED_QUERIES") != "false"
 
 	cmsClient := cms.NewCMSClient(cms.Config{
 		BaseURL:         cmsServiceURL,
-		MediaBaseURL:   cmsMediaBaseURL,
+		MediaRewriter:   mediaStrategy,
 		Token:           cmsServiceToken,
 		RequestTimeout:  10 * time.Second,
 		Cache:           cmsCache,
 		DefaultCacheTTL: 24 * time.Hour,
+		StaleCacheTTL:   7 * 24 * time.Hour,
+		CachePolicies:   cmsCachePolicies,
+		PricingRules:    pricingRules,
+		Retry: cms.RetryConfig{
+			MaxRetries: cmsMaxRetries,
+		},
+		Breaker: cms.BreakerConfig{
+			FailureThreshold: cmsBreakerThreshold,
+			OpenDuration:     cmsBreakerOpenDuration,
+		},
+		DisablePersistedQueries: !cmsPersistedQueries,
+		MediaPlaceholders:       os.Getenv("CMS_MEDIA_PLACEHOLDERS") == "true",
+	})
+
+	// Health check route
+	app.Get("/health", func(c *fiber.Ctx) error {
+		return c.JSON(fiber.Map{
+			"status":  "ok",
+			"service": "api-gateway",
+			"cache":   cmsCache.Status(),
+			"cms":     cmsClient.BreakerStatus(),
+		})
 	})
 
 	// Initialize CMS GraphQL services
@@ -134,14 +271,44 @@ func main() {
 	showroomService := cms.NewShowroomServiceGraphQL(cmsClient)
 	governorateService := cms.NewGovernorateServiceGraphQL(cmsClient)
 	appVersionService := cms.NewAppVersionServiceGraphQL(cmsClient)
+	searchService := cms.NewSearchServiceGraphQL(cmsClient)
+
+	// Uploads and their resized variants are cached on disk (default) or in Redis with CMS_MEDIA_CACHE=redis
+	mediaCacheTTL, _ := time.ParseDuration(os.Getenv("CMS_MEDIA_CACHE_TTL"))
+	mediaRevalidateAfter, _ := time.ParseDuration(os.Getenv("CMS_MEDIA_REVALIDATE_AFTER"))
+	var mediaCache cache.Cache
+	if os.Getenv("CMS_MEDIA_CACHE") == "redis" {
+		mediaCache = cache.NewRedisCache(redisClient, "media:")
+	} else {
+		mediaCacheDir := os.Getenv("CMS_MEDIA_CACHE_DIR")
+		if mediaCacheDir == "" {
+			mediaCacheDir = filepath.Join(os.TempDir(), "api-gateway-media")
+		}
+		mediaCacheMaxBytes, _ := strconv.ParseInt(os.Getenv("CMS_MEDIA_CACHE_MAX_BYTES"), 10, 64)
+		if mediaCacheMaxBytes == 0 {
+			mediaCacheMaxBytes = 1 << 30 // 1GB
+		}
+		diskCache, err := cache.NewDiskCache(cache.DiskCacheConfig{
+			Dir:      mediaCacheDir,
+			MaxBytes: mediaCacheMaxBytes,
+		})
+		if err != nil {
+			log.Printf("Warning: uploads will not be cached: %v", err)
+		} else {
+			mediaCache = diskCache
+		}
+	}
 
-	app.Get("/uploads/:path", func(c *fiber.Ctx) error {
-		path := c.Params("path")
-		cmsUrlWithoutAPI := strings.TrimSuffix(cmsServiceURL, "/graphql")
-		println(cmsUrlWithoutAPI)
-		url := cmsUrlWithoutAPI + "/uploads/" + path
-		return proxy.Do(c, url)
+	mediaMaxTransforms, _ := strconv.Atoi(os.Getenv("CMS_MEDIA_MAX_TRANSFORMS"))
+	uploads := newUploadProxy(uploadProxyConfig{
+		Upstream:      strings.TrimSuffix(cmsServiceURL, "/graphql"),
+		Media:         mediaStrategy,
+		Cache:         mediaCache,
+		CacheTTL:      mediaCacheTTL,
+		Freshness:     mediaRevalidateAfter,
+		MaxTransforms: mediaMaxTransforms,
 	})
+	app.Get("/uploads/*", uploads.handle)
 
 	// Init endpoint - fetches initial data in parallel
 	app.Get("/api/init", func(c *fiber.Ctx) error {
@@ -220,9 +387,7 @@ func main() {
 
 		brands, err := brandService.GetSimplified(ctx)
 		if err != nil {
-			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-				"error": err.Error(),
-			})
+			return err
 		}
 
 		return c.JSON(brands)
@@ -234,9 +399,7 @@ func main() {
 
 		brand, err := brandService.GetByID(ctx, id)
 		if err != nil {
-			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
-				"error": "Brand not found",
-			})
+			return err
 		}
 
 		return c.JSON(brand)
@@ -249,14 +412,29 @@ func main() {
 
 		carModels, err := carModelService.GetByBrandID(ctx, brandID)
 		if err != nil {
-			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-				"error": err.Error(),
-			})
+			return err
 		}
 
 		return c.JSON(carModels)
 	})
 
+	// Car catalog: variants filtered, sorted and paged by Strapi
+	cmsGroup.Get("/cars", func(c *fiber.Ctx) error {
+		ctx := c.UserContext()
+
+		query, err := cms.ParseCarCatalogQuery(c.Queries())
+		if err != nil {
+			return err
+		}
+
+		page, err := carModelService.GetCatalog(ctx, query)
+		if err != nil {
+			return err
+		}
+
+		return c.JSON(page)
+	})
+
 	// Get detailed car model by ID
 	cmsGroup.Get("/cars/:id", func(c *fiber.Ctx) error {
 		ctx := c.UserContext()
@@ -264,9 +442,7 @@ func main() {
 
 		carModel, err := carModelService.GetDetailedByID(ctx, id)
 		if err != nil {
-			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
-				"error": "Car model not found",
-			})
+			return err
 		}
 
 		return c.JSON(carModel)
@@ -279,23 +455,36 @@ func main() {
 
 		variant, err := carModelService.GetVariantByID(ctx, variantID)
 		if err != nil {
-			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
-				"error": "Variant not found",
-			})
+			return err
 		}
 
 		return c.JSON(variant)
 	})
 
+	// Compare two to four variants side by side
+	cmsGroup.Get("/compare", func(c *fiber.Ctx) error {
+		ctx := c.UserContext()
+
+		variantIDs, err := cms.ParseComparedVariants(c.Query("variants"))
+		if err != nil {
+			return err
+		}
+
+		comparison, err := carModelService.CompareVariants(ctx, variantIDs)
+		if err != nil {
+			return err
+		}
+
+		return c.JSON(comparison)
+	})
+
 	// Advertisements endpoints
 	cmsGroup.Get("/advertisements", func(c *fiber.Ctx) error {
 		ctx := c.UserContext()
 
 		advertisements, err := advertisementService.GetAll(ctx)
 		if err != nil {
-			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-				"error": err.Error(),
-			})
+			return err
 		}
 
 		return c.JSON(advertisements)
@@ -307,9 +496,7 @@ func main() {
 
 		advertisement, err := advertisementService.GetByID(ctx, id)
 		if err != nil {
-			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
-				"error": "Advertisement not found",
-			})
+			return err
 		}
 
 		return c.JSON(advertisement)
@@ -321,9 +508,7 @@ func main() {
 
 		showrooms, err := showroomService.GetAll(ctx)
 		if err != nil {
-			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-				"error": err.Error(),
-			})
+			return err
 		}
 
 		return c.JSON(showrooms)
@@ -335,9 +520,7 @@ func main() {
 
 		showroom, err := showroomService.GetByID(ctx, id)
 		if err != nil {
-			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
-				"error": "Showroom not found",
-			})
+			return err
 		}
 
 		return c.JSON(showroom)
@@ -350,17 +533,32 @@ func main() {
 
 		variants, err := showroomService.GetCarVariantsByShowroomID(ctx, showroomID)
 		if err != nil {
-			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-				"error": err.Error(),
-			})
+			return err
 		}
 
 		return c.JSON(variants)
 	})
 
-	// Cache Management Endpoint
+	// Search across brands, car models, car variants and showrooms
+	cmsGroup.Get("/search", func(c *fiber.Ctx) error {
+		ctx := c.UserContext()
+
+		request, err := cms.ParseSearchRequest(c.Queries())
+		if err != nil {
+			return err
+		}
+
+		results, err := searchService.Search(ctx, request)
+		if err != nil {
+			return err
+		}
+
+		return c.JSON(results)
+	})
+
+	// Cache Management Endpoints
 	cacheSecretKey := os.Getenv("CACHE_SECRET_KEY")
-	app.Post("/api/cache/invalidate/cms", func(c *fiber.Ctx) error {
+	cacheGroup := app.Group("/api/cache", func(c *fiber.Ctx) error {
 		// Check secret key
 		secretKey := c.Get("X-Cache-Secret-Key")
 		if secretKey == "" {
@@ -368,12 +566,57 @@ func main() {
 		}
 
 		if cacheSecretKey == "" || secretKey != cacheSecretKey {
-			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
-				"error": "Unauthorized: Invalid or missing secret key",
+			return fiber.NewError(fiber.StatusUnauthorized, "Unauthorized: Invalid or missing secret key")
+		}
+
+		return c.Next()
+	})
+
+	cacheGroup.Get("/stats", func(c *fiber.Ctx) error {
+		stats := fiber.Map{
+			"cms":   cmsClient.Stats(),
+			"local": localCache.Stats(),
+		}
+		if compressedCache != nil {
+			stats["compression"] = compressedCache.Stats()
+		}
+		return c.JSON(stats)
+	})
+
+	cacheGroup.Post("/invalidate/cms", func(c *fiber.Ctx) error {
+		// Tags take precedence over a pattern: ?tags=brand:abc,carVariant:xyz or {"tags": [...]}
+		var tags []string
+		if rawTags := c.Query("tags"); rawTags != "" {
+			for _, tag := range strings.Split(rawTags, ",") {
+				if tag = strings.TrimSpace(tag); tag != "" {
+					tags = append(tags, tag)
+				}
+			}
+		} else if len(c.Body()) > 0 {
+			var body struct {
+				Tags []string `json:"tags"`
+			}
+			if err := c.BodyParser(&body); err != nil {
+				return fiber.NewError(fiber.StatusBadRequest, "Invalid request body")
+			}
+			tags = body.Tags
+		}
+
+		if len(tags) > 0 {
+			removed, err := cmsClient.InvalidateTags(c.UserContext(), tags...)
+			if err != nil {
+				return err
+			}
+
+			return c.JSON(fiber.Map{
+				"success": true,
+				"message": "Cache invalidated successfully",
+				"tags":    tags,
+				"removed": removed,
 			})
 		}
 
-		// Get pattern from query or body
+		// Get pattern from query
 		pattern := c.Query("pattern")
 		if pattern == "" {
 			pattern = "cms:graphql:*" // Default: clear all CMS GraphQL cache
@@ -382,11 +625,7 @@ func main() {
 		// Invalidate cache
 		ctx := c.Context()
 		if err := cmsClient.InvalidateCache(ctx, pattern); err != nil {
-			log.Printf("Failed to invalidate cache: %v", err)
-			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-				"error":   "Failed to invalidate cache",
-				"details": err.Error(),
-			})
+			return err
 		}
 
 		return c.JSON(fiber.Map{
@@ -396,6 +635,12 @@ func main() {
 		})
 	})
 
+	// Strapi webhook - invalidates cache entries affected by content changes.
+	// Configure Strapi to send the shared secret in the X-Webhook-Secret header.
+	// Add ?rewarm=true to the webhook URL to re-fetch invalidated entries in the background.
+	webhook := &strapiWebhook{cms: cmsClient, secret: os.Getenv("STRAPI_WEBHOOK_SECRET")}
+	app.Post("/api/webhooks/strapi", webhook.handle)
+
 	// Proxy routes
 	authServiceURL := os.Getenv("AUTH_SERVICE_URL")
 	if authServiceURL == "" {
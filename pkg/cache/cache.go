@@ -16,15 +16,42 @@ type Cache interface {
 	Exists(ctx context.Context, key string) (bool, error)
 }
 
-// RedisCache implements Cache interface using Redis
+// MultiGetter is implemented by caches that read several keys in one round trip
+type MultiGetter interface {
+	GetMulti(ctx context.Context, keys []string) (map[string][]byte, error)
+}
+
+// GetMulti reads several keys from a cache, in one round trip if it implements MultiGetter.
+// Missing keys are left out of the result.
+func GetMulti(ctx context.Context, c Cache, keys []string) (map[string][]byte, error) {
+	if multi, ok := c.(MultiGetter); ok {
+		return multi.GetMulti(ctx, keys)
+	}
+
+	values := make(map[string][]byte, len(keys))
+	for _, key := range keys {
+		val, err := c.Get(ctx, key)
+		if err != nil {
+			return nil, err
+		}
+		if val != nil {
+			values[key] = val
+		}
+	}
+	return values, nil
+}
+
+// RedisCache implements Cache interface using Redis.
+// The client may be standalone, Sentinel-managed (failover) or a Redis Cluster;
+// multi-key commands are split per key so they never cross cluster hash slots.
 type RedisCache struct {
-	client *redis.Client
+	client redis.UniversalClient
 	prefix string
 }
 
 // NewRedisCache creates a new Redis cache instance
 // prefix is optional - if empty, no prefix is used
-func NewRedisCache(client *redis.Client, prefix string) *RedisCache {
+func NewRedisCache(client redis.UniversalClient, prefix string) *RedisCache {
 	return &RedisCache{
 		client: client,
 		prefix: prefix,
@@ -40,6 +67,37 @@ func (r *RedisCache) Get(ctx context.Context, key string) ([]byte, error) {
 	return val, err
 }
 
+// GetMulti retrieves several values in a single pipeline, one GET per key so that keys
+// in different cluster slots can be read together
+func (r *RedisCache) GetMulti(ctx context.Context, keys []string) (map[string][]byte, error) {
+	values := make(map[string][]byte, len(keys))
+	if len(keys) == 0 {
+		return values, nil
+	}
+
+	cmds, err := r.client.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		for _, key := range keys {
+			pipe.Get(ctx, r.prefix+key)
+		}
+		return nil
+	})
+	if err != nil && err != redis.Nil {
+		return nil, err
+	}
+
+	for i, cmd := range cmds {
+		val, err := cmd.(*redis.StringCmd).Bytes()
+		if err == redis.Nil {
+			continue // Cache miss
+		}
+		if err != nil {
+			return nil, err
+		}
+		values[keys[i]] = val
+	}
+	return values, nil
+}
+
 // Set stores a value in cache with TTL
 func (r *RedisCache) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
 	return r.client.Set(ctx, r.prefix+key, value, ttl).Err()
@@ -56,11 +114,23 @@ func (r *RedisCache) Delete(ctx context.Context, keys ...string) error {
 		prefixedKeys[i] = r.prefix + key
 	}
 
-	return r.client.Del(ctx, prefixedKeys...).Err()
+	_, err := r.del(ctx, prefixedKeys)
+	return err
 }
 
-// DeletePattern removes all keys matching a pattern
+// DeletePattern removes all keys matching a pattern.
+// In cluster mode every master is scanned, since each only holds its own slots.
 func (r *RedisCache) DeletePattern(ctx context.Context, pattern string) error {
+	if cluster, ok := r.client.(*redis.ClusterClient); ok {
+		return cluster.ForEachMaster(ctx, func(ctx context.Context, node *redis.Client) error {
+			return r.deletePatternOn(ctx, node, pattern)
+		})
+	}
+	return r.deletePatternOn(ctx, r.client, pattern)
+}
+
+// deletePatternOn scans a single node for keys matching pattern and deletes them
+func (r *RedisCache) deletePatternOn(ctx context.Context, node redis.Cmdable, pattern string) error {
 	var cursor uint64
 	var keys []string
 
@@ -68,7 +138,7 @@ func (r *RedisCache) DeletePattern(ctx context.Context, pattern string) error {
 	for {
 		var scanKeys []string
 		var err error
-		scanKeys, cursor, err = r.client.Scan(ctx, cursor, r.prefix+pattern, 100).Result()
+		scanKeys, cursor, err = node.Scan(ctx, cursor, r.prefix+pattern, 100).Result()
 		if err != nil {
 			return err
 		}
@@ -81,11 +151,32 @@ func (r *RedisCache) DeletePattern(ctx context.Context, pattern string) error {
 	}
 
 	// Delete all found keys
-	if len(keys) > 0 {
-		return r.client.Del(ctx, keys...).Err()
+	_, err := r.del(ctx, keys)
+	return err
+}
+
+// del deletes already-prefixed keys one command per key in a single pipeline,
+// so keys in different cluster slots can be deleted together. It returns how many existed.
+func (r *RedisCache) del(ctx context.Context, keys []string) (int64, error) {
+	if len(keys) == 0 {
+		return 0, nil
 	}
 
-	return nil
+	cmds, err := r.client.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		for _, key := range keys {
+			pipe.Del(ctx, key)
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	var deleted int64
+	for _, cmd := range cmds {
+		deleted += cmd.(*redis.IntCmd).Val()
+	}
+	return deleted, nil
 }
 
 // Exists checks if a key exists in cache
@@ -0,0 +1,110 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+)
+
+// newTestCluster returns a cluster client over two in-process Redis masters splitting the slots
+func newTestCluster(t *testing.T) (*redis.ClusterClient, []*miniredis.Miniredis) {
+	t.Helper()
+	masters := []*miniredis.Miniredis{miniredis.RunT(t), miniredis.RunT(t)}
+	client := redis.NewClusterClient(&redis.ClusterOptions{
+		ClusterSlots: func(ctx context.Context) ([]redis.ClusterSlot, error) {
+			return []redis.ClusterSlot{
+				{Start: 0, End: 8191, Nodes: []redis.ClusterNode{{Addr: masters[0].Addr()}}},
+				{Start: 8192, End: 16383, Nodes: []redis.ClusterNode{{Addr: masters[1].Addr()}}},
+			}, nil
+		},
+	})
+	t.Cleanup(func() { client.Close() })
+	return client, masters
+}
+
+func TestRedisCacheClusterDeletePattern(t *testing.T) {
+	ctx := context.Background()
+	client, masters := newTestCluster(t)
+	c := NewRedisCache(client, "cms:")
+
+	var keys []string
+	for i := range 40 {
+		key := fmt.Sprintf("graphql:%d", i)
+		keys = append(keys, key)
+		if err := c.Set(ctx, key, []byte("v"), time.Minute); err != nil {
+			t.Fatal(err)
+		}
+	}
+	c.Set(ctx, "uploads:brands/logo.png", []byte("png"), time.Minute)
+
+	// The keys must be spread over both masters for the test to mean anything
+	for i, master := range masters {
+		if len(master.Keys()) == 0 {
+			t.Fatalf("master %d holds no keys", i)
+		}
+	}
+
+	if err := c.DeletePattern(ctx, "graphql:*"); err != nil {
+		t.Fatal(err)
+	}
+	for i, master := range masters {
+		for _, key := range master.Keys() {
+			if key != "cms:uploads:brands/logo.png" {
+				t.Errorf("master %d still holds %s", i, key)
+			}
+		}
+	}
+	if exists, _ := c.Exists(ctx, "uploads:brands/logo.png"); !exists {
+		t.Error("key outside the pattern was deleted")
+	}
+
+	// Multi-key deletes span slots too
+	c.Set(ctx, "graphql:a", []byte("v"), time.Minute)
+	c.Set(ctx, "graphql:b", []byte("v"), time.Minute)
+	if err := c.Delete(ctx, "graphql:a", "graphql:b", "uploads:brands/logo.png"); err != nil {
+		t.Fatalf("Delete() across slots = %v", err)
+	}
+	for i, master := range masters {
+		if keys := master.Keys(); len(keys) != 0 {
+			t.Errorf("master %d still holds %v", i, keys)
+		}
+	}
+}
+
+func TestNewRedisClient(t *testing.T) {
+	tests := []struct {
+		config  RedisConfig
+		want    string // Client type
+		wantErr bool
+	}{
+		{RedisConfig{Addrs: []string{"localhost:6379"}}, "*redis.Client", false},
+		{RedisConfig{Mode: RedisModeStandalone, Addrs: []string{"localhost:6379"}}, "*redis.Client", false},
+		{RedisConfig{Mode: RedisModeSentinel, Addrs: []string{"localhost:26379"}, MasterName: "mymaster"}, "*redis.Client", false},
+		{RedisConfig{Mode: RedisModeSentinel, Addrs: []string{"localhost:26379"}}, "", true},
+		{RedisConfig{Mode: RedisModeCluster, Addrs: []string{"localhost:7000", "localhost:7001"}}, "*redis.ClusterClient", false},
+		{RedisConfig{Mode: "ring", Addrs: []string{"localhost:6379"}}, "", true},
+		{RedisConfig{}, "", true},
+	}
+
+	for _, test := range tests {
+		client, err := NewRedisClient(test.config)
+		if test.wantErr {
+			if err == nil {
+				t.Errorf("NewRedisClient(%+v) succeeded, want an error", test.config)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("NewRedisClient(%+v) = %v", test.config, err)
+			continue
+		}
+		if got := fmt.Sprintf("%T", client); got != test.want {
+			t.Errorf("NewRedisClient(%+v) is a %s, want %s", test.config, got, test.want)
+		}
+		client.Close()
+	}
+}
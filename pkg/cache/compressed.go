@@ -0,0 +1,187 @@
+package cache
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/klauspost/compress/zstd"
+)
+
+// compressedMagic marks zstd-compressed values. Values without it (including every
+// value written before compression was enabled) are returned as stored.
+var compressedMagic = []byte("\x00zst")
+
+// CompressedCacheConfig holds the settings of a CompressedCache
+type CompressedCacheConfig struct {
+	Threshold int // Values smaller than this many bytes are stored uncompressed (default 1024)
+}
+
+// CompressionStats holds cumulative write counters for the keys sharing a prefix since the
+// process started. They measure the compression ratio, not the size of the data currently
+// stored: overwrites, expiry and deletes are not subtracted.
+type CompressionStats struct {
+	Writes             uint64 `json:"writes"`
+	CompressedWrites   uint64 `json:"compressedWrites"`
+	RawBytesWritten    uint64 `json:"rawBytesWritten"`    // Total size of written values before compression
+	StoredBytesWritten uint64 `json:"storedBytesWritten"` // Total size of written values as handed to the cache
+}
+
+// CompressedCache implements Cache interface by compressing values with zstd before
+// handing them to another cache, and keeps per-key-prefix write counters.
+// The key prefix is everything before the last ':' (e.g. "cms:graphql").
+type CompressedCache struct {
+	inner     Cache
+	threshold int
+	encoder   *zstd.Encoder
+	decoder   *zstd.Decoder
+
+	mu    sync.Mutex
+	stats map[string]*CompressionStats
+}
+
+// NewCompressedCache wraps inner with transparent compression
+func NewCompressedCache(inner Cache, config CompressedCacheConfig) *CompressedCache {
+	if config.Threshold == 0 {
+		config.Threshold = 1024
+	}
+
+	// Only EncodeAll/DecodeAll are used, which are safe for concurrent use
+	encoder, _ := zstd.NewWriter(nil)
+	decoder, _ := zstd.NewReader(nil)
+
+	return &CompressedCache{
+		inner:     inner,
+		threshold: config.Threshold,
+		encoder:   encoder,
+		decoder:   decoder,
+		stats:     make(map[string]*CompressionStats),
+	}
+}
+
+// Stats returns a snapshot of the write counters by key prefix
+func (c *CompressedCache) Stats() map[string]CompressionStats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	stats := make(map[string]CompressionStats, len(c.stats))
+	for prefix, s := range c.stats {
+		stats[prefix] = *s
+	}
+	return stats
+}
+
+// encode compresses value if it is above the threshold and counts the write
+func (c *CompressedCache) encode(key string, value []byte) []byte {
+	stored := value
+	compressed := false
+	if len(value) >= c.threshold {
+		encoded := c.encoder.EncodeAll(value, append([]byte{}, compressedMagic...))
+		// Keep incompressible values as they are
+		if len(encoded) < len(value) {
+			stored = encoded
+			compressed = true
+		}
+	}
+
+	prefix := key
+	if i := strings.LastIndex(key, ":"); i >= 0 {
+		prefix = key[:i]
+	}
+
+	c.mu.Lock()
+	s, ok := c.stats[prefix]
+	if !ok {
+		s = &CompressionStats{}
+		c.stats[prefix] = s
+	}
+	s.Writes++
+	if compressed {
+		s.CompressedWrites++
+	}
+	s.RawBytesWritten += uint64(len(value))
+	s.StoredBytesWritten += uint64(len(stored))
+	c.mu.Unlock()
+
+	return stored
+}
+
+// decode decompresses a value written by encode; other values are returned unchanged
+func (c *CompressedCache) decode(value []byte) ([]byte, error) {
+	if !bytes.HasPrefix(value, compressedMagic) {
+		return value, nil
+	}
+	return c.decoder.DecodeAll(value[len(compressedMagic):], nil)
+}
+
+// Get retrieves and decompresses a value from cache
+func (c *CompressedCache) Get(ctx context.Context, key string) ([]byte, error) {
+	val, err := c.inner.Get(ctx, key)
+	if err != nil || val == nil {
+		return val, err
+	}
+	return c.decode(val)
+}
+
+// GetMulti retrieves and decompresses several values from cache
+func (c *CompressedCache) GetMulti(ctx context.Context, keys []string) (map[string][]byte, error) {
+	values, err := GetMulti(ctx, c.inner, keys)
+	if err != nil {
+		return nil, err
+	}
+	for key, val := range values {
+		if values[key], err = c.decode(val); err != nil {
+			return nil, err
+		}
+	}
+	return values, nil
+}
+
+// Set compresses and stores a value in cache with TTL
+func (c *CompressedCache) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	return c.inner.Set(ctx, key, c.encode(key, value), ttl)
+}
+
+// Delete removes one or more keys from cache
+func (c *CompressedCache) Delete(ctx context.Context, keys ...string) error {
+	return c.inner.Delete(ctx, keys...)
+}
+
+// DeletePattern removes all keys matching a pattern
+func (c *CompressedCache) DeletePattern(ctx context.Context, pattern string) error {
+	return c.inner.DeletePattern(ctx, pattern)
+}
+
+// Exists checks if a key exists in cache
+func (c *CompressedCache) Exists(ctx context.Context, key string) (bool, error) {
+	return c.inner.Exists(ctx, key)
+}
+
+// SetWithTags compresses and stores a tagged value
+func (c *CompressedCache) SetWithTags(ctx context.Context, key string, value []byte, ttl time.Duration, tags []string) error {
+	tagged, ok := c.inner.(TaggedCache)
+	if !ok {
+		return c.Set(ctx, key, value, ttl)
+	}
+	return tagged.SetWithTags(ctx, key, c.encode(key, value), ttl, tags)
+}
+
+// TaggedKeys returns the keys indexed under any of the given tags
+func (c *CompressedCache) TaggedKeys(ctx context.Context, tags ...string) ([]string, error) {
+	tagged, ok := c.inner.(TaggedCache)
+	if !ok {
+		return nil, ErrTagsUnsupported
+	}
+	return tagged.TaggedKeys(ctx, tags...)
+}
+
+// InvalidateTags removes every key indexed under any of the given tags
+func (c *CompressedCache) InvalidateTags(ctx context.Context, tags ...string) (int, error) {
+	tagged, ok := c.inner.(TaggedCache)
+	if !ok {
+		return 0, ErrTagsUnsupported
+	}
+	return tagged.InvalidateTags(ctx, tags...)
+}
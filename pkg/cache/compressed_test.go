@@ -0,0 +1,127 @@
+package cache
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestCompressedCacheFraming(t *testing.T) {
+	random := make([]byte, 4096)
+	rand.Read(random)
+
+	tests := []struct {
+		name           string
+		value          []byte
+		wantCompressed bool
+	}{
+		{"empty", []byte{}, false},
+		{"below threshold", bytes.Repeat([]byte("a"), 1023), false},
+		{"at threshold", bytes.Repeat([]byte("a"), 1024), true},
+		{"large json", bytes.Repeat([]byte(`{"name":"BMW X5","price":1000000},`), 200), true},
+		{"incompressible", random, false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ctx := context.Background()
+			inner := NewMemoryCache(MemoryCacheConfig{})
+			c := NewCompressedCache(inner, CompressedCacheConfig{})
+
+			if err := c.Set(ctx, "cms:graphql:key", test.value, time.Minute); err != nil {
+				t.Fatal(err)
+			}
+			stored, _ := inner.Get(ctx, "cms:graphql:key")
+			if compressed := bytes.HasPrefix(stored, compressedMagic); compressed != test.wantCompressed {
+				t.Errorf("compressed = %v, want %v", compressed, test.wantCompressed)
+			}
+			if test.wantCompressed && len(stored) >= len(test.value) {
+				t.Errorf("stored %d bytes for a %d-byte value", len(stored), len(test.value))
+			}
+			if !test.wantCompressed && !bytes.Equal(stored, test.value) {
+				t.Errorf("stored value was modified")
+			}
+
+			got, err := c.Get(ctx, "cms:graphql:key")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(got, test.value) {
+				t.Errorf("Get() returned %d bytes, want the %d bytes set", len(got), len(test.value))
+			}
+		})
+	}
+}
+
+func TestCompressedCacheReadsUncompressedValues(t *testing.T) {
+	ctx := context.Background()
+	inner := NewMemoryCache(MemoryCacheConfig{})
+	c := NewCompressedCache(inner, CompressedCacheConfig{Threshold: 16})
+
+	legacy := bytes.Repeat([]byte("written before compression was enabled "), 10)
+	inner.Set(ctx, "legacy", legacy, time.Minute)
+	c.Set(ctx, "new", legacy, time.Minute)
+
+	if got, err := c.Get(ctx, "legacy"); err != nil || !bytes.Equal(got, legacy) {
+		t.Errorf("Get(legacy) = %q, %v", got, err)
+	}
+	if got, err := c.Get(ctx, "missing"); err != nil || got != nil {
+		t.Errorf("Get(missing) = %q, %v; want nil", got, err)
+	}
+
+	values, err := c.GetMulti(ctx, []string{"legacy", "new", "missing"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string][]byte{"legacy": legacy, "new": legacy}
+	if !reflect.DeepEqual(values, want) {
+		t.Errorf("GetMulti() returned keys %v, want legacy and new decoded", keys(values))
+	}
+}
+
+func TestCompressedCacheStats(t *testing.T) {
+	ctx := context.Background()
+	c := NewCompressedCache(NewMemoryCache(MemoryCacheConfig{}), CompressedCacheConfig{Threshold: 100})
+
+	large := bytes.Repeat([]byte("x"), 1000)
+	c.Set(ctx, "cms:graphql:a", large, time.Minute)
+	c.Set(ctx, "cms:graphql:b", []byte("small"), time.Minute)
+	c.Set(ctx, "cms:rest:a", []byte("small"), time.Minute)
+	c.Set(ctx, "plain", []byte("small"), time.Minute)
+
+	stats := c.Stats()
+	graphql := stats["cms:graphql"]
+	if graphql.Writes != 2 || graphql.CompressedWrites != 1 || graphql.RawBytesWritten != 1005 {
+		t.Errorf("cms:graphql stats = %+v", graphql)
+	}
+	if graphql.StoredBytesWritten >= graphql.RawBytesWritten {
+		t.Errorf("cms:graphql stored %d bytes of %d", graphql.StoredBytesWritten, graphql.RawBytesWritten)
+	}
+
+	tests := []struct {
+		prefix string
+		want   CompressionStats
+	}{
+		{"cms:rest", CompressionStats{Writes: 1, RawBytesWritten: 5, StoredBytesWritten: 5}},
+		{"plain", CompressionStats{Writes: 1, RawBytesWritten: 5, StoredBytesWritten: 5}},
+	}
+	for _, test := range tests {
+		if got := stats[test.prefix]; got != test.want {
+			t.Errorf("%s stats = %+v, want %+v", test.prefix, got, test.want)
+		}
+	}
+	if len(stats) != 3 {
+		t.Errorf("Stats() has %d prefixes, want 3", len(stats))
+	}
+}
+
+func keys(values map[string][]byte) []string {
+	result := make([]string, 0, len(values))
+	for key := range values {
+		result = append(result, key)
+	}
+	return result
+}
@@ -0,0 +1,326 @@
+package cache
+
+import (
+	"container/list"
+	"context"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+)
+
+// DiskCacheConfig holds the location and limits of an on-disk cache
+type DiskCacheConfig struct {
+	Dir      string // Directory holding one file per entry; created if missing
+	MaxBytes int64  // Maximum total size of stored values; 0 means unlimited
+}
+
+// diskEntry is the in-memory index record of a file in the cache directory
+type diskEntry struct {
+	key       string
+	file      string
+	size      int64
+	expiresAt time.Time // zero means no expiry
+}
+
+// diskHeaderSize is the fixed part of an entry file: expiry (unix nanoseconds) and key length
+const diskHeaderSize = 8 + 4
+
+// DiskCache implements Cache interface as a bounded LRU of files in a directory, for
+// values too large or too numerous to keep in memory (e.g. transformed images).
+// The index is rebuilt from the directory at startup, so entries survive restarts.
+type DiskCache struct {
+	mu     sync.Mutex
+	dir    string
+	items  map[string]*list.Element
+	order  *list.List // front is most recently used
+	bytes  int64
+	config DiskCacheConfig
+	stats  MemoryCacheStats
+}
+
+// NewDiskCache creates an on-disk LRU cache and indexes the entries already in its directory
+func NewDiskCache(config DiskCacheConfig) (*DiskCache, error) {
+	if config.Dir == "" {
+		return nil, errors.New("disk cache directory is required")
+	}
+	if err := os.MkdirAll(config.Dir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create disk cache directory: %w", err)
+	}
+
+	d := &DiskCache{
+		dir:    config.Dir,
+		items:  make(map[string]*list.Element),
+		order:  list.New(),
+		config: config,
+	}
+	if err := d.load(); err != nil {
+		return nil, err
+	}
+	return d, nil
+}
+
+// load indexes existing entry files, least recently modified last
+func (d *DiskCache) load() error {
+	dirEntries, err := os.ReadDir(d.dir)
+	if err != nil {
+		return fmt.Errorf("failed to read disk cache directory: %w", err)
+	}
+
+	type loaded struct {
+		entry   *diskEntry
+		modTime time.Time
+	}
+	var entries []loaded
+	for _, dirEntry := range dirEntries {
+		if dirEntry.IsDir() {
+			continue
+		}
+		if filepath.Ext(dirEntry.Name()) == ".tmp" {
+			// Left behind by a write interrupted by a crash
+			_ = os.Remove(filepath.Join(d.dir, dirEntry.Name()))
+			continue
+		}
+		info, err := dirEntry.Info()
+		if err != nil {
+			continue
+		}
+		entry, err := d.readHeader(dirEntry.Name(), info.Size())
+		if err != nil {
+			_ = os.Remove(filepath.Join(d.dir, dirEntry.Name()))
+			continue
+		}
+		entries = append(entries, loaded{entry: entry, modTime: info.ModTime()})
+	}
+
+	sort.Slice(entries, func(i, j int) bool { return entries[i].modTime.After(entries[j].modTime) })
+	for _, e := range entries {
+		d.items[e.entry.key] = d.order.PushBack(e.entry)
+		d.bytes += e.entry.size
+	}
+	d.evict()
+	return nil
+}
+
+// readHeader reads the key and expiry of an entry file
+func (d *DiskCache) readHeader(name string, fileSize int64) (*diskEntry, error) {
+	f, err := os.Open(filepath.Join(d.dir, name))
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	header := make([]byte, diskHeaderSize)
+	if _, err := io.ReadFull(f, header); err != nil {
+		return nil, err
+	}
+	keyLen := int64(binary.BigEndian.Uint32(header[8:]))
+	if diskHeaderSize+keyLen > fileSize {
+		return nil, errors.New("truncated disk cache entry")
+	}
+	key := make([]byte, keyLen)
+	if _, err := io.ReadFull(f, key); err != nil {
+		return nil, err
+	}
+
+	entry := &diskEntry{key: string(key), file: name, size: fileSize - diskHeaderSize - keyLen}
+	if expiresAt := int64(binary.BigEndian.Uint64(header)); expiresAt != 0 {
+		entry.expiresAt = time.Unix(0, expiresAt)
+	}
+	return entry, nil
+}
+
+// Get retrieves a value from cache. The index is consulted under the lock; the file is
+// read after releasing it, so a slow read does not block other keys.
+func (d *DiskCache) Get(ctx context.Context, key string) ([]byte, error) {
+	d.mu.Lock()
+	element, ok := d.items[key]
+	if !ok {
+		d.stats.Misses++
+		d.mu.Unlock()
+		return nil, nil // Cache miss
+	}
+
+	entry := element.Value.(*diskEntry)
+	if !entry.expiresAt.IsZero() && time.Now().After(entry.expiresAt) {
+		d.removeElement(element)
+		d.stats.Misses++
+		d.mu.Unlock()
+		return nil, nil
+	}
+	d.order.MoveToFront(element)
+	d.mu.Unlock()
+
+	// A concurrent Set replaces the file atomically and a concurrent eviction removes it, so
+	// the read sees a whole entry or fails
+	file := filepath.Join(d.dir, entry.file)
+	data, err := os.ReadFile(file)
+	value, ok := diskValue(data, key)
+	if err != nil || !ok {
+		// Removed behind our back; treat as a miss
+		d.mu.Lock()
+		if current, exists := d.items[key]; exists && current == element {
+			d.removeElement(element)
+		}
+		d.stats.Misses++
+		d.mu.Unlock()
+		return nil, nil
+	}
+
+	// The modification time orders entries when the index is rebuilt at startup
+	now := time.Now()
+	_ = os.Chtimes(file, now, now)
+
+	d.mu.Lock()
+	d.stats.Hits++
+	d.mu.Unlock()
+	return value, nil
+}
+
+// Set stores a value in cache with TTL
+func (d *DiskCache) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	// Values larger than the whole cache are not stored
+	if d.config.MaxBytes > 0 && int64(len(value)) > d.config.MaxBytes {
+		return nil
+	}
+
+	entry := &diskEntry{key: key, file: diskFileName(key), size: int64(len(value))}
+	var expiresAt int64
+	if ttl > 0 {
+		entry.expiresAt = time.Now().Add(ttl)
+		expiresAt = entry.expiresAt.UnixNano()
+	}
+
+	data := make([]byte, diskHeaderSize, diskHeaderSize+len(key)+len(value))
+	binary.BigEndian.PutUint64(data, uint64(expiresAt))
+	binary.BigEndian.PutUint32(data[8:], uint32(len(key)))
+	data = append(data, key...)
+	data = append(data, value...)
+
+	// Write to a temporary file first so readers never see a partial entry
+	tmp, err := os.CreateTemp(d.dir, entry.file+"-*.tmp")
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if err := os.Rename(tmp.Name(), filepath.Join(d.dir, entry.file)); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	if element, ok := d.items[key]; ok {
+		d.unlink(element)
+	}
+	d.items[key] = d.order.PushFront(entry)
+	d.bytes += entry.size
+	d.evict()
+	return nil
+}
+
+// Delete removes one or more keys from cache
+func (d *DiskCache) Delete(ctx context.Context, keys ...string) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	for _, key := range keys {
+		if element, ok := d.items[key]; ok {
+			d.removeElement(element)
+		}
+	}
+	return nil
+}
+
+// DeletePattern removes all keys matching a glob pattern, matched as Redis does (see matchPattern)
+func (d *DiskCache) DeletePattern(ctx context.Context, pattern string) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	for key, element := range d.items {
+		if matchPattern(pattern, key) {
+			d.removeElement(element)
+		}
+	}
+	return nil
+}
+
+// Exists checks if a key exists in cache
+func (d *DiskCache) Exists(ctx context.Context, key string) (bool, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	element, ok := d.items[key]
+	if !ok {
+		return false, nil
+	}
+	entry := element.Value.(*diskEntry)
+	return entry.expiresAt.IsZero() || time.Now().Before(entry.expiresAt), nil
+}
+
+// Stats returns a snapshot of the cache's counters
+func (d *DiskCache) Stats() MemoryCacheStats {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	stats := d.stats
+	stats.Entries = d.order.Len()
+	stats.Bytes = d.bytes
+	return stats
+}
+
+// evict removes least recently used entries until within MaxBytes; the caller must hold the lock
+func (d *DiskCache) evict() {
+	for d.config.MaxBytes > 0 && d.bytes > d.config.MaxBytes {
+		d.removeElement(d.order.Back())
+		d.stats.Evictions++
+	}
+}
+
+// removeElement unlinks an entry and deletes its file; the caller must hold the lock
+func (d *DiskCache) removeElement(element *list.Element) {
+	entry := d.unlink(element)
+	_ = os.Remove(filepath.Join(d.dir, entry.file))
+}
+
+// unlink removes an entry from the index only; the caller must hold the lock
+func (d *DiskCache) unlink(element *list.Element) *diskEntry {
+	entry := d.order.Remove(element).(*diskEntry)
+	delete(d.items, entry.key)
+	d.bytes -= entry.size
+	return entry
+}
+
+// diskValue returns the value of an entry file's contents if it holds key
+func diskValue(data []byte, key string) ([]byte, bool) {
+	if len(data) < diskHeaderSize {
+		return nil, false
+	}
+	keyEnd := diskHeaderSize + int(binary.BigEndian.Uint32(data[8:]))
+	if keyEnd > len(data) || string(data[diskHeaderSize:keyEnd]) != key {
+		return nil, false
+	}
+	return data[keyEnd:], true
+}
+
+// diskFileName derives a file name from a cache key
+func diskFileName(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,147 @@
+package cache
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// newTestDiskCache creates a disk cache in dir with a size limit
+func newTestDiskCache(t *testing.T, dir string, maxBytes int64) *DiskCache {
+	t.Helper()
+	d, err := NewDiskCache(DiskCacheConfig{Dir: dir, MaxBytes: maxBytes})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return d
+}
+
+// diskKeys returns which of keys the cache holds
+func diskKeys(t *testing.T, d *DiskCache, keys ...string) map[string]bool {
+	t.Helper()
+	held := make(map[string]bool)
+	for _, key := range keys {
+		ok, err := d.Exists(context.Background(), key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		held[key] = ok
+	}
+	return held
+}
+
+func TestDiskCacheEvictsLeastRecentlyUsed(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	d := newTestDiskCache(t, dir, 30)
+
+	d.Set(ctx, "a", make([]byte, 10), time.Hour)
+	d.Set(ctx, "b", make([]byte, 10), time.Hour)
+	d.Set(ctx, "c", make([]byte, 10), time.Hour)
+	if value, _ := d.Get(ctx, "a"); len(value) != 10 { // a is now the most recently used
+		t.Fatalf("Get(a) = %d bytes, want 10", len(value))
+	}
+	d.Set(ctx, "d", make([]byte, 10), time.Hour)
+
+	held := diskKeys(t, d, "a", "b", "c", "d")
+	if !held["a"] || held["b"] || !held["c"] || !held["d"] {
+		t.Errorf("held %v, want b evicted", held)
+	}
+	if _, err := os.Stat(filepath.Join(dir, diskFileName("b"))); !os.IsNotExist(err) {
+		t.Errorf("file of evicted entry still exists: %v", err)
+	}
+	stats := d.Stats()
+	if stats.Entries != 3 || stats.Bytes != 30 || stats.Evictions != 1 {
+		t.Errorf("stats = %+v, want 3 entries, 30 bytes, 1 eviction", stats)
+	}
+
+	// A value larger than the whole cache is not stored and evicts nothing
+	d.Set(ctx, "huge", make([]byte, 31), time.Hour)
+	if held := diskKeys(t, d, "huge", "a", "c", "d"); held["huge"] || !held["a"] || !held["c"] || !held["d"] {
+		t.Errorf("held %v after an oversized Set", held)
+	}
+}
+
+func TestDiskCacheReplaceAccountsSize(t *testing.T) {
+	ctx := context.Background()
+	d := newTestDiskCache(t, t.TempDir(), 30)
+
+	d.Set(ctx, "a", make([]byte, 20), time.Hour)
+	d.Set(ctx, "a", make([]byte, 5), time.Hour)
+	d.Set(ctx, "b", make([]byte, 20), time.Hour)
+
+	if held := diskKeys(t, d, "a", "b"); !held["a"] || !held["b"] {
+		t.Errorf("held %v, want both: replacing a must release its old size", held)
+	}
+	if stats := d.Stats(); stats.Bytes != 25 || stats.Evictions != 0 {
+		t.Errorf("stats = %+v, want 25 bytes and no evictions", stats)
+	}
+}
+
+func TestDiskCacheExpiry(t *testing.T) {
+	ctx := context.Background()
+	d := newTestDiskCache(t, t.TempDir(), 0)
+
+	d.Set(ctx, "short", []byte("x"), time.Millisecond)
+	d.Set(ctx, "forever", []byte("y"), 0)
+	time.Sleep(5 * time.Millisecond)
+
+	if value, _ := d.Get(ctx, "short"); value != nil {
+		t.Errorf("Get(short) = %q after its TTL, want a miss", value)
+	}
+	if value, _ := d.Get(ctx, "forever"); string(value) != "y" {
+		t.Errorf("Get(forever) = %q, want y", value)
+	}
+}
+
+func TestDiskCacheReloadsDirectory(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	d := newTestDiskCache(t, dir, 0)
+	d.Set(ctx, "variant|/uploads/a.png|w128", []byte("png"), time.Hour)
+	d.Set(ctx, "expired", []byte("old"), time.Millisecond)
+	if err := os.WriteFile(filepath.Join(dir, "partial-123.tmp"), []byte("junk"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "truncated"), []byte{0, 0}, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(5 * time.Millisecond)
+
+	reloaded := newTestDiskCache(t, dir, 0)
+	if value, _ := reloaded.Get(ctx, "variant|/uploads/a.png|w128"); string(value) != "png" {
+		t.Errorf("Get() after reload = %q, want png", value)
+	}
+	if value, _ := reloaded.Get(ctx, "expired"); value != nil {
+		t.Errorf("expired entry survived the reload: %q", value)
+	}
+	for _, name := range []string{"partial-123.tmp", "truncated"} {
+		if _, err := os.Stat(filepath.Join(dir, name)); !os.IsNotExist(err) {
+			t.Errorf("%s was not cleaned up: %v", name, err)
+		}
+	}
+}
+
+func TestDiskCacheReloadEvictsOldestFirst(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	d := newTestDiskCache(t, dir, 0)
+	for _, key := range []string{"old", "mid", "new"} {
+		d.Set(ctx, key, make([]byte, 10), time.Hour)
+	}
+	// Order by modification time, as a restart sees them
+	base := time.Now().Add(-time.Hour)
+	for i, key := range []string{"old", "mid", "new"} {
+		modTime := base.Add(time.Duration(i) * time.Minute)
+		if err := os.Chtimes(filepath.Join(dir, diskFileName(key)), modTime, modTime); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	reloaded := newTestDiskCache(t, dir, 20)
+	if held := diskKeys(t, reloaded, "old", "mid", "new"); held["old"] || !held["mid"] || !held["new"] {
+		t.Errorf("held %v after reloading into a smaller cache, want old evicted", held)
+	}
+}
@@ -0,0 +1,97 @@
+package cache
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"log"
+	"os"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// InvalidationMessage describes keys removed from the shared cache by one instance
+type InvalidationMessage struct {
+	Source  string   `json:"source"`            // Instance that performed the invalidation
+	Keys    []string `json:"keys,omitempty"`    // Exact keys removed
+	Pattern string   `json:"pattern,omitempty"` // Glob pattern removed
+}
+
+// InvalidationBus broadcasts invalidations over Redis pub/sub so that every instance
+// can evict the same entries from its in-process cache
+type InvalidationBus struct {
+	client  redis.UniversalClient
+	channel string
+	source  string
+}
+
+// NewInvalidationBus creates a bus publishing on the given Redis channel.
+// Each bus gets a unique source ID so instances can skip their own messages.
+func NewInvalidationBus(client redis.UniversalClient, channel string) *InvalidationBus {
+	hostname, _ := os.Hostname()
+	suffix := make([]byte, 4)
+	_, _ = rand.Read(suffix)
+
+	return &InvalidationBus{
+		client:  client,
+		channel: channel,
+		source:  hostname + "-" + hex.EncodeToString(suffix),
+	}
+}
+
+// Publish announces that keys, or all keys matching pattern, were removed
+func (b *InvalidationBus) Publish(ctx context.Context, keys []string, pattern string) error {
+	if len(keys) == 0 && pattern == "" {
+		return nil
+	}
+
+	payload, err := json.Marshal(InvalidationMessage{
+		Source:  b.source,
+		Keys:    keys,
+		Pattern: pattern,
+	})
+	if err != nil {
+		return err
+	}
+
+	return b.client.Publish(ctx, b.channel, payload).Err()
+}
+
+// Subscribe evicts entries from local as other instances publish invalidations.
+// It blocks until ctx is done; the underlying subscription reconnects automatically.
+// Messages published while disconnected are lost, so local's MaxTTL still bounds staleness.
+func (b *InvalidationBus) Subscribe(ctx context.Context, local *MemoryCache) {
+	pubsub := b.client.Subscribe(ctx, b.channel)
+	defer pubsub.Close()
+
+	messages := pubsub.Channel()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case message, ok := <-messages:
+			if !ok {
+				return
+			}
+
+			var invalidation InvalidationMessage
+			if err := json.Unmarshal([]byte(message.Payload), &invalidation); err != nil {
+				log.Printf("Invalid cache invalidation message: %v", err)
+				continue
+			}
+			if invalidation.Source == b.source {
+				continue // Already applied locally
+			}
+
+			if len(invalidation.Keys) > 0 {
+				_ = local.Delete(ctx, invalidation.Keys...)
+			}
+			if invalidation.Pattern != "" {
+				if err := local.DeletePattern(ctx, invalidation.Pattern); err != nil {
+					log.Printf("Invalid cache invalidation pattern %q: %v", invalidation.Pattern, err)
+				}
+			}
+		}
+	}
+}
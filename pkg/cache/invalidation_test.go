@@ -0,0 +1,117 @@
+package cache
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+)
+
+// testInstance is one gateway instance: a local tier in front of the shared Redis, with its own bus
+type testInstance struct {
+	local  *MemoryCache
+	tiered *TieredCache
+}
+
+// newTestInstances creates n instances sharing server, each subscribed to the invalidation bus
+func newTestInstances(t *testing.T, server *miniredis.Miniredis, n int) []testInstance {
+	t.Helper()
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	instances := make([]testInstance, n)
+	for i := range instances {
+		client := redis.NewClient(&redis.Options{Addr: server.Addr()})
+		t.Cleanup(func() { client.Close() })
+
+		local := NewMemoryCache(MemoryCacheConfig{})
+		bus := NewInvalidationBus(client, "cms:invalidations")
+		go bus.Subscribe(ctx, local)
+		instances[i] = testInstance{local: local, tiered: NewTieredCache(local, NewRedisCache(client, "cms:"), bus)}
+	}
+
+	waitUntil(t, func() bool { return server.PubSubNumSub("cms:invalidations")["cms:invalidations"] == n })
+	return instances
+}
+
+// waitUntil polls condition until it holds or a second has passed
+func waitUntil(t *testing.T, condition func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(time.Second)
+	for !condition() {
+		if time.Now().After(deadline) {
+			t.Fatal("condition not met within 1s")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestInvalidationBusEvictsOtherInstances(t *testing.T) {
+	tests := []struct {
+		name       string
+		invalidate func(ctx context.Context, c *TieredCache) error
+		evicted    []string
+		kept       []string
+	}{
+		{
+			"keys",
+			func(ctx context.Context, c *TieredCache) error { return c.Delete(ctx, "graphql:a") },
+			[]string{"graphql:a"},
+			[]string{"graphql:b", "uploads:brands/logo.png"},
+		},
+		{
+			"pattern",
+			func(ctx context.Context, c *TieredCache) error { return c.DeletePattern(ctx, "uploads:*") },
+			[]string{"uploads:brands/logo.png"},
+			[]string{"graphql:a", "graphql:b"},
+		},
+		{
+			"tags",
+			func(ctx context.Context, c *TieredCache) error {
+				_, err := c.InvalidateTags(ctx, "brand:bmw")
+				return err
+			},
+			[]string{"graphql:b"},
+			[]string{"graphql:a", "uploads:brands/logo.png"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ctx := context.Background()
+			instances := newTestInstances(t, miniredis.RunT(t), 2)
+			writer, reader := instances[0], instances[1]
+
+			writer.tiered.Set(ctx, "graphql:a", []byte("a"), time.Minute)
+			writer.tiered.SetWithTags(ctx, "graphql:b", []byte("b"), time.Minute, []string{"brand:bmw"})
+			writer.tiered.Set(ctx, "uploads:brands/logo.png", []byte("png"), time.Minute)
+			for _, key := range append(test.evicted, test.kept...) {
+				// Reading through the second instance copies the entry into its local tier
+				if val, _ := reader.tiered.Get(ctx, key); val == nil {
+					t.Fatalf("%s missing from the shared cache", key)
+				}
+			}
+
+			if err := test.invalidate(ctx, writer.tiered); err != nil {
+				t.Fatal(err)
+			}
+
+			for _, key := range test.evicted {
+				waitUntil(t, func() bool {
+					ok, _ := reader.local.Exists(ctx, key)
+					return !ok
+				})
+				if ok, _ := writer.local.Exists(ctx, key); ok {
+					t.Errorf("%s still in the invalidating instance's local tier", key)
+				}
+			}
+			for _, key := range test.kept {
+				if ok, _ := reader.local.Exists(ctx, key); !ok {
+					t.Errorf("%s evicted from the other instance", key)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,177 @@
+package cache
+
+import (
+	"container/list"
+	"context"
+	"sync"
+	"time"
+)
+
+// MemoryCacheConfig holds the limits of an in-process cache
+type MemoryCacheConfig struct {
+	MaxEntries int           // Maximum number of entries; 0 means unlimited
+	MaxBytes   int64         // Maximum total size of stored values; 0 means unlimited
+	MaxTTL     time.Duration // Upper bound for entry TTLs; 0 keeps the TTL passed to Set
+}
+
+// MemoryCacheStats is a snapshot of an in-process cache's counters
+type MemoryCacheStats struct {
+	Entries   int    `json:"entries"`
+	Bytes     int64  `json:"bytes"`
+	Hits      uint64 `json:"hits"`
+	Misses    uint64 `json:"misses"`
+	Evictions uint64 `json:"evictions"`
+}
+
+// memoryEntry is a single value in the LRU list
+type memoryEntry struct {
+	key       string
+	value     []byte
+	expiresAt time.Time // zero means no expiry
+}
+
+// MemoryCache implements Cache interface as a bounded in-process LRU.
+// Returned values are shared with the cache and must not be modified.
+type MemoryCache struct {
+	mu     sync.Mutex
+	items  map[string]*list.Element
+	order  *list.List // front is most recently used
+	bytes  int64
+	config MemoryCacheConfig
+	stats  MemoryCacheStats
+}
+
+// NewMemoryCache creates a new in-process LRU cache with the given limits
+func NewMemoryCache(config MemoryCacheConfig) *MemoryCache {
+	return &MemoryCache{
+		items:  make(map[string]*list.Element),
+		order:  list.New(),
+		config: config,
+	}
+}
+
+// Get retrieves a value from cache
+func (m *MemoryCache) Get(ctx context.Context, key string) ([]byte, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	element, ok := m.items[key]
+	if !ok {
+		m.stats.Misses++
+		return nil, nil // Cache miss
+	}
+
+	entry := element.Value.(*memoryEntry)
+	if !entry.expiresAt.IsZero() && time.Now().After(entry.expiresAt) {
+		m.removeElement(element)
+		m.stats.Misses++
+		return nil, nil
+	}
+
+	m.order.MoveToFront(element)
+	m.stats.Hits++
+	return entry.value, nil
+}
+
+// GetMulti retrieves several values from cache
+func (m *MemoryCache) GetMulti(ctx context.Context, keys []string) (map[string][]byte, error) {
+	values := make(map[string][]byte, len(keys))
+	for _, key := range keys {
+		if val, _ := m.Get(ctx, key); val != nil {
+			values[key] = val
+		}
+	}
+	return values, nil
+}
+
+// Set stores a value in cache with TTL, capped at MaxTTL
+func (m *MemoryCache) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	if m.config.MaxTTL > 0 && (ttl <= 0 || ttl > m.config.MaxTTL) {
+		ttl = m.config.MaxTTL
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if element, ok := m.items[key]; ok {
+		m.removeElement(element)
+	}
+
+	// Values larger than the whole cache are not stored
+	if m.config.MaxBytes > 0 && int64(len(value)) > m.config.MaxBytes {
+		return nil
+	}
+
+	entry := &memoryEntry{key: key, value: value}
+	if ttl > 0 {
+		entry.expiresAt = time.Now().Add(ttl)
+	}
+	m.items[key] = m.order.PushFront(entry)
+	m.bytes += int64(len(value))
+
+	// Evict least recently used entries until within limits
+	for (m.config.MaxEntries > 0 && m.order.Len() > m.config.MaxEntries) ||
+		(m.config.MaxBytes > 0 && m.bytes > m.config.MaxBytes) {
+		m.removeElement(m.order.Back())
+		m.stats.Evictions++
+	}
+
+	return nil
+}
+
+// Delete removes one or more keys from cache
+func (m *MemoryCache) Delete(ctx context.Context, keys ...string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, key := range keys {
+		if element, ok := m.items[key]; ok {
+			m.removeElement(element)
+		}
+	}
+	return nil
+}
+
+// DeletePattern removes all keys matching a glob pattern, matched as Redis does (see matchPattern)
+func (m *MemoryCache) DeletePattern(ctx context.Context, pattern string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for key, element := range m.items {
+		if matchPattern(pattern, key) {
+			m.removeElement(element)
+		}
+	}
+	return nil
+}
+
+// Exists checks if a key exists in cache
+func (m *MemoryCache) Exists(ctx context.Context, key string) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	element, ok := m.items[key]
+	if !ok {
+		return false, nil
+	}
+	entry := element.Value.(*memoryEntry)
+	return entry.expiresAt.IsZero() || time.Now().Before(entry.expiresAt), nil
+}
+
+// Stats returns a snapshot of the cache's counters
+func (m *MemoryCache) Stats() MemoryCacheStats {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	stats := m.stats
+	stats.Entries = m.order.Len()
+	stats.Bytes = m.bytes
+	return stats
+}
+
+// removeElement unlinks an entry; the caller must hold the lock
+func (m *MemoryCache) removeElement(element *list.Element) {
+	entry := m.order.Remove(element).(*memoryEntry)
+	delete(m.items, entry.key)
+	m.bytes -= int64(len(entry.value))
+}
@@ -0,0 +1,79 @@
+package cache
+
+// matchPattern reports whether key matches a glob pattern with the semantics of Redis
+// KEYS and SCAN MATCH, so that in-process caches delete the same keys as Redis:
+// * matches any run of bytes (including /), ? a single byte, [...] a set of bytes or
+// ranges (a-z, negated with [^...]), and \ escapes the next byte. Unlike path.Match
+// it never fails; an unterminated [ ends the pattern.
+func matchPattern(pattern, key string) bool {
+	p, s := 0, 0
+	for p < len(pattern) && s < len(key) {
+		switch pattern[p] {
+		case '*':
+			for p+1 < len(pattern) && pattern[p+1] == '*' {
+				p++
+			}
+			if p+1 == len(pattern) {
+				return true
+			}
+			for ; s < len(key); s++ {
+				if matchPattern(pattern[p+1:], key[s:]) {
+					return true
+				}
+			}
+			return false
+		case '?':
+			s++
+		case '[':
+			p++
+			negated := p < len(pattern) && pattern[p] == '^'
+			if negated {
+				p++
+			}
+			matched := false
+			for {
+				if p >= len(pattern) {
+					p-- // Unterminated set: stop at the end of the pattern
+					break
+				}
+				if pattern[p] == '\\' && len(pattern)-p >= 2 {
+					p++
+					matched = matched || pattern[p] == key[s]
+				} else if pattern[p] == ']' {
+					break
+				} else if len(pattern)-p >= 3 && pattern[p+1] == '-' {
+					start, end := pattern[p], pattern[p+2]
+					if start > end {
+						start, end = end, start
+					}
+					p += 2
+					matched = matched || (key[s] >= start && key[s] <= end)
+				} else {
+					matched = matched || pattern[p] == key[s]
+				}
+				p++
+			}
+			if matched == negated {
+				return false
+			}
+			s++
+		case '\\':
+			if len(pattern)-p >= 2 {
+				p++
+			}
+			fallthrough
+		default:
+			if pattern[p] != key[s] {
+				return false
+			}
+			s++
+		}
+		p++
+		if s == len(key) {
+			for p < len(pattern) && pattern[p] == '*' {
+				p++
+			}
+		}
+	}
+	return p == len(pattern) && s == len(key)
+}
@@ -0,0 +1,130 @@
+package cache
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestMatchPattern(t *testing.T) {
+	tests := []struct {
+		pattern, key string
+		want         bool
+	}{
+		{"cms:graphql:*", "cms:graphql:abc", true},
+		{"cms:graphql:*", "cms:rest:abc", false},
+		{"uploads:*", "uploads:brands/logo.png", true},
+		{"uploads:*.png", "uploads:brands/2024/logo.png", true},
+		{"*", "a/b/c", true},
+		{"**", "a/b", true},
+		{"a*b*c", "a/x/b/y/c", true},
+		{"a*b*c", "a/x/b/y/d", false},
+		{"h?llo", "hello", true},
+		{"h?llo", "h/llo", true},
+		{"h?llo", "hllo", false},
+		{"h[ae]llo", "hallo", true},
+		{"h[ae]llo", "hillo", false},
+		{"h[^e]llo", "hallo", true},
+		{"h[^e]llo", "hello", false},
+		{"h[a-c]llo", "hbllo", true},
+		{"h[c-a]llo", "hbllo", true},
+		{"h[a-c]llo", "hdllo", false},
+		{`h[\]]llo`, "h]llo", true},
+		{`h\*llo`, "h*llo", true},
+		{`h\*llo`, "hello", false},
+		{`h\?`, "h?", true},
+		{"h[ab", "ha", true},
+		{"h[ab", "hab", false},
+		{"[", "a", false},
+		{`a\`, `a\`, true},
+		{"abc", "abc", true},
+		{"abc", "abcd", false},
+		{"abc*", "abc", true},
+		{"", "", true},
+		{"", "a", false},
+		{"*", "", false},
+	}
+
+	for _, test := range tests {
+		if got := matchPattern(test.pattern, test.key); got != test.want {
+			t.Errorf("matchPattern(%q, %q) = %v, want %v", test.pattern, test.key, got, test.want)
+		}
+	}
+}
+
+// TestDeletePatternMatchesRedis deletes the same patterns through a TieredCache and from a
+// DiskCache; the in-process caches must keep exactly the keys Redis keeps
+func TestDeletePatternMatchesRedis(t *testing.T) {
+	keys := []string{
+		"graphql:abc",
+		"graphql:abd",
+		"uploads:brands/logo.png",
+		"uploads:brands/2024/logo.jpg",
+		"uploads:models/x5.png",
+		"media:placeholder:f1",
+		"tag:brand:bmw",
+		"h*llo",
+		"hello",
+	}
+	patterns := []string{
+		"graphql:*",
+		"graphql:ab[cd]",
+		"graphql:ab[^c]",
+		"uploads:*",
+		"uploads:brands/*",
+		"uploads:*.png",
+		"uploads:*/logo.*",
+		"*placeholder*",
+		"tag:brand:???",
+		`h\*llo`,
+		"h?llo",
+		"*",
+	}
+
+	for _, pattern := range patterns {
+		t.Run(pattern, func(t *testing.T) {
+			ctx := context.Background()
+			local := NewMemoryCache(MemoryCacheConfig{})
+			remote, _ := newTestRedisCache(t, "cms:")
+			tiered := NewTieredCache(local, remote, nil)
+			disk, err := NewDiskCache(DiskCacheConfig{Dir: t.TempDir()})
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, key := range keys {
+				tiered.Set(ctx, key, []byte(key), time.Minute)
+				disk.Set(ctx, key, []byte(key), time.Minute)
+			}
+
+			if err := tiered.DeletePattern(ctx, pattern); err != nil {
+				t.Fatal(err)
+			}
+			if err := disk.DeletePattern(ctx, pattern); err != nil {
+				t.Fatal(err)
+			}
+
+			var localKept, remoteKept, diskKept []string
+			for _, key := range keys {
+				if ok, _ := local.Exists(ctx, key); ok {
+					localKept = append(localKept, key)
+				}
+				if ok, _ := remote.Exists(ctx, key); ok {
+					remoteKept = append(remoteKept, key)
+				}
+				if ok, _ := disk.Exists(ctx, key); ok {
+					diskKept = append(diskKept, key)
+				}
+			}
+			if !slices.Equal(localKept, remoteKept) {
+				t.Errorf("local tier kept %q, Redis kept %q", localKept, remoteKept)
+			}
+			if !slices.Equal(diskKept, remoteKept) {
+				t.Errorf("disk cache kept %q, Redis kept %q", diskKept, remoteKept)
+			}
+			if len(remoteKept) == len(keys) {
+				t.Errorf("pattern deleted nothing")
+			}
+		})
+	}
+}
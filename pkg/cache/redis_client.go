@@ -0,0 +1,58 @@
+package cache
+
+import (
+	"fmt"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// Redis deployment modes
+const (
+	RedisModeStandalone = "standalone"
+	RedisModeSentinel   = "sentinel"
+	RedisModeCluster    = "cluster"
+)
+
+// RedisConfig holds connection settings for a standalone, Sentinel or Cluster deployment
+type RedisConfig struct {
+	Mode             string   // standalone (default), sentinel or cluster
+	Addrs            []string // Server address; Sentinel addresses in sentinel mode; seed nodes in cluster mode
+	Password         string
+	DB               int    // Ignored in cluster mode
+	MasterName       string // Sentinel mode: name of the monitored master
+	SentinelPassword string // Sentinel mode: password of the Sentinels, if different
+}
+
+// NewRedisClient creates a client for the configured deployment mode
+func NewRedisClient(config RedisConfig) (redis.UniversalClient, error) {
+	if len(config.Addrs) == 0 {
+		return nil, fmt.Errorf("redis: no addresses configured")
+	}
+
+	switch config.Mode {
+	case "", RedisModeStandalone:
+		return redis.NewClient(&redis.Options{
+			Addr:     config.Addrs[0],
+			Password: config.Password,
+			DB:       config.DB,
+		}), nil
+	case RedisModeSentinel:
+		if config.MasterName == "" {
+			return nil, fmt.Errorf("redis: sentinel mode requires a master name")
+		}
+		return redis.NewFailoverClient(&redis.FailoverOptions{
+			MasterName:       config.MasterName,
+			SentinelAddrs:    config.Addrs,
+			SentinelPassword: config.SentinelPassword,
+			Password:         config.Password,
+			DB:               config.DB,
+		}), nil
+	case RedisModeCluster:
+		return redis.NewClusterClient(&redis.ClusterOptions{
+			Addrs:    config.Addrs,
+			Password: config.Password,
+		}), nil
+	default:
+		return nil, fmt.Errorf("redis: unknown mode %q", config.Mode)
+	}
+}
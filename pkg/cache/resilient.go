@@ -0,0 +1,328 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"log"
+	"sync"
+	"time"
+)
+
+// Cache states reported by ResilientCache
+const (
+	StateConnected = "connected" // Serving from the primary cache
+	StateDegraded  = "degraded"  // Primary unreachable or breaker open; serving from the fallback
+)
+
+// maxPendingInvalidations bounds the invalidations queued while degraded;
+// beyond it ResilientCacheConfig.OverflowPattern is invalidated on reconnect instead
+const maxPendingInvalidations = 1000
+
+// ResilientCacheConfig holds the circuit breaker settings of a ResilientCache
+type ResilientCacheConfig struct {
+	FailureThreshold int           // Consecutive primary errors that trip the breaker (default 5)
+	ProbeInterval    time.Duration // How often the primary is probed while degraded (default 5s)
+	ProbeTimeout     time.Duration // Timeout of a single probe (default 2s)
+	OverflowPattern  string        // Keys invalidated on reconnect when too many invalidations were queued (default "*")
+}
+
+// ResilientCacheStatus is a snapshot of a ResilientCache's state for health output
+type ResilientCacheStatus struct {
+	State               string    `json:"state"`
+	Since               time.Time `json:"since"`
+	ConsecutiveFailures int       `json:"consecutiveFailures"`
+	LastError           string    `json:"lastError,omitempty"`
+}
+
+// ResilientCache implements Cache interface over a primary cache (usually Redis) that may be
+// unavailable. It starts degraded, serving from the fallback cache, and switches to the primary
+// once a probe succeeds. Repeated primary errors trip a circuit breaker that switches back to
+// the fallback until probes succeed again. Invalidations made while degraded are replayed
+// against the primary before it is used again, so it never serves invalidated entries.
+type ResilientCache struct {
+	primary  Cache
+	fallback Cache
+	probe    func(ctx context.Context) error
+	config   ResilientCacheConfig
+
+	mu       sync.Mutex
+	status   ResilientCacheStatus
+	pending  []func(ctx context.Context) error
+	overflow bool
+}
+
+// NewResilientCache creates a cache that starts degraded; call Run to start probing the primary
+func NewResilientCache(primary, fallback Cache, probe func(ctx context.Context) error, config ResilientCacheConfig) *ResilientCache {
+	if config.FailureThreshold == 0 {
+		config.FailureThreshold = 5
+	}
+	if config.ProbeInterval == 0 {
+		config.ProbeInterval = 5 * time.Second
+	}
+	if config.ProbeTimeout == 0 {
+		config.ProbeTimeout = 2 * time.Second
+	}
+	if config.OverflowPattern == "" {
+		config.OverflowPattern = "*"
+	}
+
+	return &ResilientCache{
+		primary:  primary,
+		fallback: fallback,
+		probe:    probe,
+		config:   config,
+		status: ResilientCacheStatus{
+			State: StateDegraded,
+			Since: time.Now(),
+		},
+	}
+}
+
+// Run probes the primary while degraded until ctx is done
+func (r *ResilientCache) Run(ctx context.Context) {
+	ticker := time.NewTicker(r.config.ProbeInterval)
+	defer ticker.Stop()
+
+	for {
+		if !r.connected() {
+			r.tryReconnect(ctx)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Status returns the current state for health output
+func (r *ResilientCache) Status() ResilientCacheStatus {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.status
+}
+
+// tryReconnect probes the primary, replays pending invalidations and switches to it on success
+func (r *ResilientCache) tryReconnect(ctx context.Context) {
+	probeCtx, cancel := context.WithTimeout(ctx, r.config.ProbeTimeout)
+	defer cancel()
+
+	if err := r.probe(probeCtx); err != nil {
+		r.mu.Lock()
+		r.status.LastError = err.Error()
+		r.mu.Unlock()
+		return
+	}
+
+	r.mu.Lock()
+	pending, overflow := r.pending, r.overflow
+	r.pending, r.overflow = nil, false
+	r.mu.Unlock()
+
+	if err := r.replay(ctx, pending, overflow); err != nil {
+		log.Printf("Cache: primary reachable but replaying invalidations failed: %v", err)
+		r.mu.Lock()
+		// Keep the invalidations for the next attempt, ahead of any queued meanwhile
+		r.pending = append(pending, r.pending...)
+		r.overflow = r.overflow || overflow
+		r.status.LastError = err.Error()
+		r.mu.Unlock()
+		return
+	}
+
+	r.mu.Lock()
+	r.status = ResilientCacheStatus{State: StateConnected, Since: time.Now()}
+	r.mu.Unlock()
+	log.Println("Cache: primary connected")
+}
+
+// replay applies invalidations made while degraded to the primary
+func (r *ResilientCache) replay(ctx context.Context, pending []func(ctx context.Context) error, overflow bool) error {
+	if overflow {
+		return r.primary.DeletePattern(ctx, r.config.OverflowPattern)
+	}
+	for _, invalidate := range pending {
+		if err := invalidate(ctx); err != nil && !errors.Is(err, ErrTagsUnsupported) {
+			return err
+		}
+	}
+	return nil
+}
+
+// connected reports whether the primary is in use
+func (r *ResilientCache) connected() bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.status.State == StateConnected
+}
+
+// observe records the outcome of a primary operation and trips the breaker on repeated errors
+func (r *ResilientCache) observe(ctx context.Context, err error) {
+	// Errors caused by the caller going away say nothing about the primary
+	if ctx.Err() != nil || errors.Is(err, ErrTagsUnsupported) {
+		return
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if err == nil {
+		r.status.ConsecutiveFailures = 0
+		return
+	}
+
+	r.status.ConsecutiveFailures++
+	r.status.LastError = err.Error()
+	if r.status.State == StateConnected && r.status.ConsecutiveFailures >= r.config.FailureThreshold {
+		r.status.State = StateDegraded
+		r.status.Since = time.Now()
+		log.Printf("Cache: circuit open after %d consecutive errors, last: %v", r.status.ConsecutiveFailures, err)
+	}
+}
+
+// queueInvalidation records an invalidation to replay against the primary on reconnect
+func (r *ResilientCache) queueInvalidation(invalidate func(ctx context.Context) error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if len(r.pending) >= maxPendingInvalidations {
+		r.pending = nil
+		r.overflow = true
+	}
+	if !r.overflow {
+		r.pending = append(r.pending, invalidate)
+	}
+}
+
+// Get retrieves a value from the primary, or the fallback while degraded or on error
+func (r *ResilientCache) Get(ctx context.Context, key string) ([]byte, error) {
+	if r.connected() {
+		val, err := r.primary.Get(ctx, key)
+		r.observe(ctx, err)
+		if err == nil {
+			return val, nil
+		}
+	}
+	return r.fallback.Get(ctx, key)
+}
+
+// GetMulti retrieves several values from the primary, or the fallback while degraded or on error
+func (r *ResilientCache) GetMulti(ctx context.Context, keys []string) (map[string][]byte, error) {
+	if r.connected() {
+		values, err := GetMulti(ctx, r.primary, keys)
+		r.observe(ctx, err)
+		if err == nil {
+			return values, nil
+		}
+	}
+	return GetMulti(ctx, r.fallback, keys)
+}
+
+// Set stores a value in the primary, or the fallback while degraded or on error
+func (r *ResilientCache) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	if r.connected() {
+		err := r.primary.Set(ctx, key, value, ttl)
+		r.observe(ctx, err)
+		if err == nil {
+			return nil
+		}
+	}
+	return r.fallback.Set(ctx, key, value, ttl)
+}
+
+// Delete removes keys from the primary; while degraded they are removed from the
+// fallback and queued for the primary
+func (r *ResilientCache) Delete(ctx context.Context, keys ...string) error {
+	if r.connected() {
+		err := r.primary.Delete(ctx, keys...)
+		r.observe(ctx, err)
+		if err == nil {
+			return nil
+		}
+	}
+
+	r.queueInvalidation(func(ctx context.Context) error {
+		return r.primary.Delete(ctx, keys...)
+	})
+	return r.fallback.Delete(ctx, keys...)
+}
+
+// DeletePattern removes matching keys from the primary; while degraded they are removed
+// from the fallback and the pattern is queued for the primary
+func (r *ResilientCache) DeletePattern(ctx context.Context, pattern string) error {
+	if r.connected() {
+		err := r.primary.DeletePattern(ctx, pattern)
+		r.observe(ctx, err)
+		if err == nil {
+			return nil
+		}
+	}
+
+	r.queueInvalidation(func(ctx context.Context) error {
+		return r.primary.DeletePattern(ctx, pattern)
+	})
+	return r.fallback.DeletePattern(ctx, pattern)
+}
+
+// Exists checks if a key exists in the primary, or the fallback while degraded or on error
+func (r *ResilientCache) Exists(ctx context.Context, key string) (bool, error) {
+	if r.connected() {
+		ok, err := r.primary.Exists(ctx, key)
+		r.observe(ctx, err)
+		if err == nil {
+			return ok, nil
+		}
+	}
+	return r.fallback.Exists(ctx, key)
+}
+
+// SetWithTags stores a tagged value in the primary; while degraded the value is stored
+// in the fallback without tags
+func (r *ResilientCache) SetWithTags(ctx context.Context, key string, value []byte, ttl time.Duration, tags []string) error {
+	if tagged, ok := r.primary.(TaggedCache); ok && r.connected() {
+		err := tagged.SetWithTags(ctx, key, value, ttl, tags)
+		r.observe(ctx, err)
+		if err == nil {
+			return nil
+		}
+	}
+	return r.fallback.Set(ctx, key, value, ttl)
+}
+
+// TaggedKeys returns the keys indexed under any of the given tags in the primary.
+// Tags are not indexed while degraded, so ErrTagsUnsupported is returned.
+func (r *ResilientCache) TaggedKeys(ctx context.Context, tags ...string) ([]string, error) {
+	if tagged, ok := r.primary.(TaggedCache); ok && r.connected() {
+		keys, err := tagged.TaggedKeys(ctx, tags...)
+		r.observe(ctx, err)
+		if err == nil {
+			return keys, nil
+		}
+	}
+	return nil, ErrTagsUnsupported
+}
+
+// InvalidateTags removes tagged keys from the primary. While degraded the fallback cannot
+// resolve tags, so ErrTagsUnsupported is returned (callers fall back to DeletePattern) and
+// the tags are queued for the primary.
+func (r *ResilientCache) InvalidateTags(ctx context.Context, tags ...string) (int, error) {
+	tagged, ok := r.primary.(TaggedCache)
+	if !ok {
+		return 0, ErrTagsUnsupported
+	}
+
+	if r.connected() {
+		removed, err := tagged.InvalidateTags(ctx, tags...)
+		r.observe(ctx, err)
+		if err == nil {
+			return removed, nil
+		}
+	}
+
+	r.queueInvalidation(func(ctx context.Context) error {
+		_, err := tagged.InvalidateTags(ctx, tags...)
+		return err
+	})
+	return 0, ErrTagsUnsupported
+}
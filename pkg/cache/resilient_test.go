@@ -0,0 +1,128 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+)
+
+// newTestResilientCache wraps a Redis cache on server; probes succeed while reachable is true
+func newTestResilientCache(t *testing.T, config ResilientCacheConfig) (*ResilientCache, *RedisCache, *miniredis.Miniredis, *bool) {
+	t.Helper()
+	primary, server := newTestRedisCache(t, "cms:")
+	reachable := true
+	probe := func(ctx context.Context) error {
+		if !reachable {
+			return errors.New("connection refused")
+		}
+		return nil
+	}
+	config.FailureThreshold = 1
+	return NewResilientCache(primary, NewMemoryCache(MemoryCacheConfig{}), probe, config), primary, server, &reachable
+}
+
+func TestResilientCacheReplaysInvalidationsOnRecovery(t *testing.T) {
+	ctx := context.Background()
+	c, primary, server, reachable := newTestResilientCache(t, ResilientCacheConfig{})
+
+	c.tryReconnect(ctx)
+	if state := c.Status().State; state != StateConnected {
+		t.Fatalf("state = %s after a successful probe, want connected", state)
+	}
+	c.Set(ctx, "graphql:a", []byte("a"), time.Minute)
+	c.SetWithTags(ctx, "graphql:b", []byte("b"), time.Minute, []string{"brand:bmw"})
+	c.Set(ctx, "graphql:c", []byte("c"), time.Minute)
+	c.Set(ctx, "uploads:brands/logo.png", []byte("png"), time.Minute)
+
+	// Redis goes away: the first failed invalidation trips the breaker
+	server.SetError("LOADING Redis is loading the dataset in memory")
+	*reachable = false
+	if err := c.Delete(ctx, "graphql:a"); err != nil {
+		t.Fatalf("Delete() while Redis fails = %v, want the fallback's result", err)
+	}
+	if state := c.Status().State; state != StateDegraded {
+		t.Fatalf("state = %s after a failed delete, want degraded", state)
+	}
+	c.DeletePattern(ctx, "uploads:*")
+	if _, err := c.InvalidateTags(ctx, "brand:bmw"); !errors.Is(err, ErrTagsUnsupported) {
+		t.Errorf("InvalidateTags() while degraded = %v, want ErrTagsUnsupported", err)
+	}
+
+	// Probes fail while Redis is down; nothing is replayed
+	c.tryReconnect(ctx)
+	if state := c.Status().State; state != StateDegraded {
+		t.Fatalf("state = %s after a failed probe, want degraded", state)
+	}
+
+	// Reachable again, but the replay fails: the invalidations stay queued
+	*reachable = true
+	c.tryReconnect(ctx)
+	if state := c.Status().State; state != StateDegraded {
+		t.Fatalf("state = %s after a failed replay, want degraded", state)
+	}
+	if len(c.pending) != 3 {
+		t.Fatalf("%d invalidations queued after a failed replay, want 3", len(c.pending))
+	}
+
+	server.SetError("")
+	c.tryReconnect(ctx)
+	if state := c.Status().State; state != StateConnected {
+		t.Fatalf("state = %s after recovery, want connected", state)
+	}
+	for key, want := range map[string]bool{"graphql:a": false, "graphql:b": false, "graphql:c": true, "uploads:brands/logo.png": false} {
+		if exists, _ := primary.Exists(ctx, key); exists != want {
+			t.Errorf("%s exists in Redis = %v after the replay, want %v", key, exists, want)
+		}
+	}
+	if len(c.pending) != 0 {
+		t.Errorf("%d invalidations still queued", len(c.pending))
+	}
+}
+
+func TestResilientCacheInvalidatesOverflowPatternOnRecovery(t *testing.T) {
+	ctx := context.Background()
+	c, primary, server, _ := newTestResilientCache(t, ResilientCacheConfig{OverflowPattern: "graphql:*"})
+
+	c.tryReconnect(ctx)
+	c.Set(ctx, "graphql:a", []byte("a"), time.Minute)
+	c.Set(ctx, "uploads:brands/logo.png", []byte("png"), time.Minute)
+
+	server.SetError("LOADING Redis is loading the dataset in memory")
+	for range maxPendingInvalidations + 1 {
+		c.Delete(ctx, "graphql:other")
+	}
+	if !c.overflow || len(c.pending) != 0 {
+		t.Fatalf("overflow = %v with %d queued, want the queue replaced by the overflow pattern", c.overflow, len(c.pending))
+	}
+
+	server.SetError("")
+	c.tryReconnect(ctx)
+	if exists, _ := primary.Exists(ctx, "graphql:a"); exists {
+		t.Error("graphql:a survived the overflow invalidation")
+	}
+	if exists, _ := primary.Exists(ctx, "uploads:brands/logo.png"); !exists {
+		t.Error("uploads:brands/logo.png outside the overflow pattern was removed")
+	}
+}
+
+func TestResilientCacheServesFallbackWhileDegraded(t *testing.T) {
+	ctx := context.Background()
+	c, primary, _, _ := newTestResilientCache(t, ResilientCacheConfig{})
+
+	// Starts degraded until the first probe
+	c.Set(ctx, "graphql:a", []byte("fallback"), time.Minute)
+	if val, _ := c.Get(ctx, "graphql:a"); string(val) != "fallback" {
+		t.Errorf("Get() while degraded = %q, want the fallback's value", val)
+	}
+	if exists, _ := primary.Exists(ctx, "graphql:a"); exists {
+		t.Error("write while degraded reached Redis")
+	}
+
+	c.tryReconnect(ctx)
+	if val, _ := c.Get(ctx, "graphql:a"); val != nil {
+		t.Errorf("Get() when connected = %q, want Redis' miss", val)
+	}
+}
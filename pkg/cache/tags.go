@@ -0,0 +1,237 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// TaggedCache is implemented by caches that index keys by tag so that
+// entries can be invalidated by what they contain instead of by key pattern
+type TaggedCache interface {
+	Cache
+	SetWithTags(ctx context.Context, key string, value []byte, ttl time.Duration, tags []string) error
+	TaggedKeys(ctx context.Context, tags ...string) ([]string, error)
+	InvalidateTags(ctx context.Context, tags ...string) (int, error)
+}
+
+// ErrTagsUnsupported is returned by tag operations of caches whose backing store cannot index tags
+var ErrTagsUnsupported = errors.New("cache does not support tags")
+
+// tagIndexKey returns the (unprefixed) key of the set holding all keys tagged with tag
+func tagIndexKey(tag string) string {
+	return "tag:" + tag
+}
+
+// tagIndexKeys returns the prefixed index set keys for tags
+func (r *RedisCache) tagIndexKeys(tags []string) []string {
+	indexKeys := make([]string, len(tags))
+	for i, tag := range tags {
+		indexKeys[i] = r.prefix + tagIndexKey(tag)
+	}
+	return indexKeys
+}
+
+// SetWithTags stores a value and adds its key to the index set of every tag in one
+// MULTI/EXEC transaction, so an invalidation never runs between the write and its indexing
+// and a fresh value cannot outlive its index entries. In cluster mode the transaction is
+// split per hash slot; the index sets are written before the value, so a concurrent
+// invalidation can leave a dangling index entry but never an unindexed key.
+// Index sets live at least as long as the longest-lived key they reference, so broad
+// tags are kept alive by every write; RunTagIndexPruning removes their expired keys.
+// Requires Redis 7+ for EXPIRE NX/GT.
+func (r *RedisCache) SetWithTags(ctx context.Context, key string, value []byte, ttl time.Duration, tags []string) error {
+	_, err := r.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		for _, indexKey := range r.tagIndexKeys(tags) {
+			pipe.SAdd(ctx, indexKey, key)
+			if ttl > 0 {
+				pipe.ExpireNX(ctx, indexKey, ttl)
+				pipe.ExpireGT(ctx, indexKey, ttl)
+			}
+		}
+		pipe.Set(ctx, r.prefix+key, value, ttl)
+		return nil
+	})
+	return err
+}
+
+// TaggedKeys returns the keys indexed under any of the given tags.
+// Index sets are read one by one since they may live in different cluster slots.
+func (r *RedisCache) TaggedKeys(ctx context.Context, tags ...string) ([]string, error) {
+	if len(tags) == 0 {
+		return nil, nil
+	}
+
+	cmds, err := r.client.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		for _, indexKey := range r.tagIndexKeys(tags) {
+			pipe.SMembers(ctx, indexKey)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	seen := make(map[string]struct{})
+	var keys []string
+	for _, cmd := range cmds {
+		for _, key := range cmd.(*redis.StringSliceCmd).Val() {
+			if _, ok := seen[key]; !ok {
+				seen[key] = struct{}{}
+				keys = append(keys, key)
+			}
+		}
+	}
+	return keys, nil
+}
+
+// InvalidateTags removes every key indexed under any of the given tags, along with the index sets.
+// It returns the number of cache keys that were removed.
+func (r *RedisCache) InvalidateTags(ctx context.Context, tags ...string) (int, error) {
+	if len(tags) == 0 {
+		return 0, nil
+	}
+
+	// Collect the union of all tagged keys
+	keys, err := r.TaggedKeys(ctx, tags...)
+	if err != nil {
+		return 0, err
+	}
+
+	prefixedKeys := make([]string, len(keys))
+	for i, key := range keys {
+		prefixedKeys[i] = r.prefix + key
+	}
+
+	deleted, err := r.del(ctx, prefixedKeys)
+	if err != nil {
+		return 0, err
+	}
+
+	if _, err := r.del(ctx, r.tagIndexKeys(tags)); err != nil {
+		return 0, err
+	}
+	return int(deleted), nil
+}
+
+// RunTagIndexPruning prunes the tag index sets every interval until ctx is cancelled
+func (r *RedisCache) RunTagIndexPruning(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		pruned, err := r.PruneTagIndexes(ctx)
+		if err != nil {
+			log.Printf("Cache: pruning tag indexes failed: %v", err)
+		} else if pruned > 0 {
+			log.Printf("Cache: pruned %d expired keys from tag indexes", pruned)
+		}
+	}
+}
+
+// PruneTagIndexes removes keys that no longer exist (expired or deleted) from every tag
+// index set and returns how many were removed. Sets are read with SSCAN so large ones
+// are never loaded at once; a set left empty is deleted by Redis.
+func (r *RedisCache) PruneTagIndexes(ctx context.Context) (int, error) {
+	var indexKeys []string
+	collect := func(ctx context.Context, node redis.Cmdable) error {
+		var cursor uint64
+		for {
+			keys, next, err := node.Scan(ctx, cursor, r.prefix+tagIndexKey("*"), 100).Result()
+			if err != nil {
+				return err
+			}
+			indexKeys = append(indexKeys, keys...)
+			if cursor = next; cursor == 0 {
+				return nil
+			}
+		}
+	}
+	var err error
+	if cluster, ok := r.client.(*redis.ClusterClient); ok {
+		var mu sync.Mutex
+		err = cluster.ForEachMaster(ctx, func(ctx context.Context, node *redis.Client) error {
+			mu.Lock()
+			defer mu.Unlock()
+			return collect(ctx, node)
+		})
+	} else {
+		err = collect(ctx, r.client)
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	pruned := 0
+	for _, indexKey := range indexKeys {
+		removed, err := r.pruneTagIndex(ctx, indexKey)
+		if err != nil {
+			return pruned, err
+		}
+		pruned += removed
+	}
+	return pruned, nil
+}
+
+// pruneTagIndex removes the members of one index set whose key no longer exists
+func (r *RedisCache) pruneTagIndex(ctx context.Context, indexKey string) (int, error) {
+	pruned := 0
+	var cursor uint64
+	for {
+		members, next, err := r.client.SScan(ctx, indexKey, cursor, "", 100).Result()
+		if err != nil {
+			return pruned, err
+		}
+
+		if len(members) > 0 {
+			cmds, err := r.client.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+				for _, member := range members {
+					pipe.Exists(ctx, r.prefix+member)
+				}
+				return nil
+			})
+			if err != nil {
+				return pruned, err
+			}
+
+			var expired []interface{}
+			for i, cmd := range cmds {
+				if cmd.(*redis.IntCmd).Val() == 0 {
+					expired = append(expired, members[i])
+				}
+			}
+			if len(expired) > 0 {
+				if err := r.client.SRem(ctx, indexKey, expired...).Err(); err != nil {
+					return pruned, err
+				}
+				pruned += len(expired)
+			}
+		}
+
+		if cursor = next; cursor == 0 {
+			return pruned, nil
+		}
+	}
+}
+
+func (n *NoOpCache) SetWithTags(ctx context.Context, key string, value []byte, ttl time.Duration, tags []string) error {
+	return nil
+}
+
+func (n *NoOpCache) TaggedKeys(ctx context.Context, tags ...string) ([]string, error) {
+	return nil, nil
+}
+
+func (n *NoOpCache) InvalidateTags(ctx context.Context, tags ...string) (int, error) {
+	return 0, nil
+}
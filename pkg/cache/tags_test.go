@@ -0,0 +1,86 @@
+package cache
+
+import (
+	"context"
+	"reflect"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+)
+
+// newTestRedisCache starts an in-process Redis server and returns a cache on it
+func newTestRedisCache(t *testing.T, prefix string) (*RedisCache, *miniredis.Miniredis) {
+	t.Helper()
+	server := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: server.Addr()})
+	t.Cleanup(func() { client.Close() })
+	return NewRedisCache(client, prefix), server
+}
+
+func TestRedisCacheSetWithTags(t *testing.T) {
+	ctx := context.Background()
+	c, server := newTestRedisCache(t, "cms:")
+
+	c.SetWithTags(ctx, "graphql:a", []byte("a"), time.Minute, []string{"brand", "brand:bmw"})
+	c.SetWithTags(ctx, "graphql:b", []byte("b"), time.Hour, []string{"brand", "brand:audi"})
+	c.SetWithTags(ctx, "graphql:c", []byte("c"), 10*time.Second, []string{"brand"})
+
+	tests := []struct {
+		indexKey string
+		members  []string
+		ttl      time.Duration // The longest TTL of its keys
+	}{
+		{"cms:tag:brand", []string{"graphql:a", "graphql:b", "graphql:c"}, time.Hour},
+		{"cms:tag:brand:bmw", []string{"graphql:a"}, time.Minute},
+		{"cms:tag:brand:audi", []string{"graphql:b"}, time.Hour},
+	}
+	for _, test := range tests {
+		members, err := server.Members(test.indexKey)
+		if err != nil || !reflect.DeepEqual(members, test.members) {
+			t.Errorf("%s = %v, %v; want %v", test.indexKey, members, err, test.members)
+		}
+		if ttl := server.TTL(test.indexKey); ttl != test.ttl {
+			t.Errorf("%s TTL = %s, want %s", test.indexKey, ttl, test.ttl)
+		}
+	}
+	if value, _ := server.Get("cms:graphql:a"); value != "a" {
+		t.Errorf("cms:graphql:a = %q, want a", value)
+	}
+}
+
+func TestRedisCacheInvalidateTags(t *testing.T) {
+	ctx := context.Background()
+	c, server := newTestRedisCache(t, "cms:")
+
+	c.SetWithTags(ctx, "graphql:a", []byte("a"), time.Minute, []string{"brand", "brand:bmw"})
+	c.SetWithTags(ctx, "graphql:b", []byte("b"), time.Minute, []string{"brand", "brand:audi"})
+	c.SetWithTags(ctx, "graphql:c", []byte("c"), time.Minute, []string{"showroom"})
+
+	keys, err := c.TaggedKeys(ctx, "brand:bmw", "brand:audi")
+	slices.Sort(keys)
+	if err != nil || !reflect.DeepEqual(keys, []string{"graphql:a", "graphql:b"}) {
+		t.Errorf("TaggedKeys() = %v, %v", keys, err)
+	}
+
+	removed, err := c.InvalidateTags(ctx, "brand:bmw")
+	if err != nil || removed != 1 {
+		t.Fatalf("InvalidateTags() = %d, %v; want 1", removed, err)
+	}
+	for key, want := range map[string]bool{"cms:graphql:a": false, "cms:graphql:b": true, "cms:graphql:c": true, "cms:tag:brand:bmw": false} {
+		if exists := server.Exists(key); exists != want {
+			t.Errorf("%s exists = %v, want %v", key, exists, want)
+		}
+	}
+
+	// The broad tag still lists the removed key until it is pruned
+	pruned, err := c.PruneTagIndexes(ctx)
+	if err != nil || pruned != 1 {
+		t.Errorf("PruneTagIndexes() = %d, %v; want 1", pruned, err)
+	}
+	if members, _ := server.Members("cms:tag:brand"); !reflect.DeepEqual(members, []string{"graphql:b"}) {
+		t.Errorf("cms:tag:brand = %v after pruning", members)
+	}
+}
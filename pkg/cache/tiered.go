@@ -0,0 +1,152 @@
+package cache
+
+import (
+	"context"
+	"time"
+)
+
+// TieredCache implements Cache interface with an in-process cache in front of a shared one
+// (usually RedisCache). Reads check the local cache first and populate it from the remote
+// cache on a miss; writes and deletes go to both layers.
+type TieredCache struct {
+	local  *MemoryCache
+	remote Cache
+	bus    *InvalidationBus
+}
+
+// NewTieredCache creates a two-tier cache.
+// bus is optional: when set, deletes are broadcast so other instances evict their local copies.
+// Without it, the local cache's MaxTTL bounds how long an instance may serve data that
+// another instance already invalidated.
+func NewTieredCache(local *MemoryCache, remote Cache, bus *InvalidationBus) *TieredCache {
+	return &TieredCache{
+		local:  local,
+		remote: remote,
+		bus:    bus,
+	}
+}
+
+// Get retrieves a value from the local cache, falling back to the remote cache
+func (t *TieredCache) Get(ctx context.Context, key string) ([]byte, error) {
+	if val, _ := t.local.Get(ctx, key); val != nil {
+		return val, nil
+	}
+
+	val, err := t.remote.Get(ctx, key)
+	if err != nil || val == nil {
+		return val, err
+	}
+
+	// The remaining remote TTL is unknown; the local MaxTTL bounds the copy's lifetime
+	_ = t.local.Set(ctx, key, val, 0)
+	return val, nil
+}
+
+// GetMulti retrieves several values from the local cache and reads the ones it lacks
+// from the remote cache in one round trip
+func (t *TieredCache) GetMulti(ctx context.Context, keys []string) (map[string][]byte, error) {
+	values, _ := t.local.GetMulti(ctx, keys)
+	var missing []string
+	for _, key := range keys {
+		if _, ok := values[key]; !ok {
+			missing = append(missing, key)
+		}
+	}
+	if len(missing) == 0 {
+		return values, nil
+	}
+
+	remote, err := GetMulti(ctx, t.remote, missing)
+	if err != nil {
+		return nil, err
+	}
+	for key, val := range remote {
+		_ = t.local.Set(ctx, key, val, 0)
+		values[key] = val
+	}
+	return values, nil
+}
+
+// Set stores a value in both layers
+func (t *TieredCache) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	if err := t.remote.Set(ctx, key, value, ttl); err != nil {
+		return err
+	}
+	return t.local.Set(ctx, key, value, ttl)
+}
+
+// Delete removes one or more keys from both layers and from other instances' local caches
+func (t *TieredCache) Delete(ctx context.Context, keys ...string) error {
+	_ = t.local.Delete(ctx, keys...)
+	if err := t.remote.Delete(ctx, keys...); err != nil {
+		return err
+	}
+	return t.publish(ctx, keys, "")
+}
+
+// DeletePattern removes all keys matching a pattern from both layers and from other instances' local caches
+func (t *TieredCache) DeletePattern(ctx context.Context, pattern string) error {
+	_ = t.local.DeletePattern(ctx, pattern)
+	if err := t.remote.DeletePattern(ctx, pattern); err != nil {
+		return err
+	}
+	return t.publish(ctx, nil, pattern)
+}
+
+// publish broadcasts an invalidation if a bus is configured
+func (t *TieredCache) publish(ctx context.Context, keys []string, pattern string) error {
+	if t.bus == nil {
+		return nil
+	}
+	return t.bus.Publish(ctx, keys, pattern)
+}
+
+// Exists checks if a key exists in either layer
+func (t *TieredCache) Exists(ctx context.Context, key string) (bool, error) {
+	if ok, _ := t.local.Exists(ctx, key); ok {
+		return true, nil
+	}
+	return t.remote.Exists(ctx, key)
+}
+
+// SetWithTags stores a value in both layers, indexing its tags in the remote cache
+func (t *TieredCache) SetWithTags(ctx context.Context, key string, value []byte, ttl time.Duration, tags []string) error {
+	tagged, ok := t.remote.(TaggedCache)
+	if !ok {
+		return t.Set(ctx, key, value, ttl)
+	}
+
+	if err := tagged.SetWithTags(ctx, key, value, ttl, tags); err != nil {
+		return err
+	}
+	return t.local.Set(ctx, key, value, ttl)
+}
+
+// TaggedKeys returns the keys indexed under any of the given tags in the remote cache
+func (t *TieredCache) TaggedKeys(ctx context.Context, tags ...string) ([]string, error) {
+	tagged, ok := t.remote.(TaggedCache)
+	if !ok {
+		return nil, ErrTagsUnsupported
+	}
+	return tagged.TaggedKeys(ctx, tags...)
+}
+
+// InvalidateTags removes every key indexed under any of the given tags from both layers
+func (t *TieredCache) InvalidateTags(ctx context.Context, tags ...string) (int, error) {
+	tagged, ok := t.remote.(TaggedCache)
+	if !ok {
+		return 0, ErrTagsUnsupported
+	}
+
+	keys, err := tagged.TaggedKeys(ctx, tags...)
+	if err != nil {
+		return 0, err
+	}
+	_ = t.local.Delete(ctx, keys...)
+
+	removed, err := tagged.InvalidateTags(ctx, tags...)
+	if err != nil {
+		return 0, err
+	}
+	return removed, t.publish(ctx, keys, "")
+}
@@ -0,0 +1,246 @@
+// Package imaging resizes and re-encodes images with pure-Go codecs
+package imaging
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	_ "image/gif" // register the GIF decoder
+	"image/jpeg"
+	"image/png"
+	"strconv"
+	"strings"
+
+	"github.com/HugoSmits86/nativewebp"
+	"golang.org/x/image/draw"
+	_ "golang.org/x/image/webp" // register the WebP decoder
+)
+
+// Limits on decoded images, so a single request cannot exhaust memory
+const (
+	MaxSourcePixels = 50_000_000 // Largest source image that is decoded
+	DefaultQuality  = 80
+)
+
+// Sizes and Qualities are the only widths, heights and JPEG qualities produced, so the
+// number of variants per image stays small. Requested values are snapped up to the next
+// allowed one; values above the largest are rejected.
+var (
+	Sizes     = []int{64, 128, 256, 384, 512, 640, 768, 1024, 1280, 1600, 1920, 2560, 3840}
+	Qualities = []int{50, 60, 70, 80, 90, 100}
+)
+
+// ErrInvalidOptions is returned for transformation parameters out of range
+var ErrInvalidOptions = errors.New("invalid image options")
+
+// ErrUnsupported is returned for sources that are not a decodable image or too large to decode
+var ErrUnsupported = errors.New("unsupported image")
+
+// Fit controls how an image is resized into the requested box
+type Fit string
+
+const (
+	FitContain Fit = "contain" // Scale to fit inside the box, keeping the aspect ratio (default)
+	FitCover   Fit = "cover"   // Scale to fill the box, keeping the aspect ratio, and crop the overflow
+	FitFill    Fit = "fill"    // Stretch to exactly the box
+)
+
+// Options describe a transformation. A zero width or height is derived from the aspect ratio.
+type Options struct {
+	Width   int
+	Height  int
+	Fit     Fit
+	Quality int // JPEG quality, one of Qualities; not used for PNG and WebP output
+}
+
+// ParseOptions parses the width, height, fit and quality request parameters; empty values are
+// left at their defaults and the others are snapped to Sizes and Qualities
+func ParseOptions(width, height, fit, quality string) (Options, error) {
+	opts := Options{Fit: FitContain, Quality: DefaultQuality}
+
+	var err error
+	if opts.Width, err = parseDimension(width); err != nil {
+		return Options{}, fmt.Errorf("%w: width %v", ErrInvalidOptions, err)
+	}
+	if opts.Height, err = parseDimension(height); err != nil {
+		return Options{}, fmt.Errorf("%w: height %v", ErrInvalidOptions, err)
+	}
+
+	switch Fit(strings.ToLower(fit)) {
+	case "", FitContain:
+	case FitCover:
+		opts.Fit = FitCover
+	case FitFill:
+		opts.Fit = FitFill
+	default:
+		return Options{}, fmt.Errorf("%w: fit must be contain, cover or fill", ErrInvalidOptions)
+	}
+
+	if quality != "" {
+		q, err := strconv.Atoi(quality)
+		if err != nil || q < 1 {
+			return Options{}, fmt.Errorf("%w: quality must be between 1 and 100", ErrInvalidOptions)
+		}
+		if opts.Quality, err = snap(q, Qualities); err != nil {
+			return Options{}, fmt.Errorf("%w: quality must be between 1 and 100", ErrInvalidOptions)
+		}
+	}
+
+	return opts, nil
+}
+
+// parseDimension parses an optional pixel size, snapped to Sizes
+func parseDimension(value string) (int, error) {
+	if value == "" {
+		return 0, nil
+	}
+	largest := Sizes[len(Sizes)-1]
+	n, err := strconv.Atoi(value)
+	if err != nil || n < 1 {
+		return 0, fmt.Errorf("must be between 1 and %d", largest)
+	}
+	if n, err = snap(n, Sizes); err != nil {
+		return 0, fmt.Errorf("must be between 1 and %d", largest)
+	}
+	return n, nil
+}
+
+// snap returns the smallest allowed value not below n
+func snap(n int, allowed []int) (int, error) {
+	for _, value := range allowed {
+		if value >= n {
+			return value, nil
+		}
+	}
+	return 0, fmt.Errorf("%d is above %d", n, allowed[len(allowed)-1])
+}
+
+// Resizes reports whether the options change the image size
+func (o Options) Resizes() bool {
+	return o.Width > 0 || o.Height > 0
+}
+
+// Key is a stable representation of the options for cache keys. A zero Quality is left
+// out, for images encoded without one.
+func (o Options) Key() string {
+	key := fmt.Sprintf("w%d-h%d-%s", o.Width, o.Height, o.Fit)
+	if o.Quality > 0 {
+		key += fmt.Sprintf("-q%d", o.Quality)
+	}
+	return key
+}
+
+// Lossless returns the options with Quality cleared, as used for PNG and WebP output
+func (o Options) Lossless() Options {
+	o.Quality = 0
+	return o
+}
+
+// Result is an encoded, transformed image
+type Result struct {
+	Data        []byte
+	ContentType string
+	Lossless    bool // Encoded as PNG or WebP, so Options.Quality did not apply
+}
+
+// Transform decodes an image, resizes it according to opts and re-encodes it. Images are
+// never enlarged. PNG and GIF sources and any image with transparency (e.g. a WebP with
+// alpha) are encoded losslessly: as WebP if acceptWebP is set, as PNG otherwise. Other
+// images become JPEG at opts.Quality; the only pure-Go WebP encoder is lossless, which
+// would make photos larger than JPEG.
+func Transform(source []byte, opts Options, acceptWebP bool) (*Result, error) {
+	config, format, err := image.DecodeConfig(bytes.NewReader(source))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrUnsupported, err)
+	}
+	if config.Width*config.Height > MaxSourcePixels {
+		return nil, fmt.Errorf("%w: %dx%d source is too large", ErrUnsupported, config.Width, config.Height)
+	}
+
+	img, _, err := image.Decode(bytes.NewReader(source))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrUnsupported, err)
+	}
+	lossless := format == "png" || format == "gif" || !opaque(img)
+	img = resize(img, opts)
+
+	var buf bytes.Buffer
+	result := &Result{Lossless: lossless}
+	switch {
+	case lossless && acceptWebP:
+		err = nativewebp.Encode(&buf, img, nil)
+		result.ContentType = "image/webp"
+	case lossless:
+		err = png.Encode(&buf, img)
+		result.ContentType = "image/png"
+	default:
+		err = jpeg.Encode(&buf, img, &jpeg.Options{Quality: opts.Quality})
+		result.ContentType = "image/jpeg"
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode %s: %w", result.ContentType, err)
+	}
+	result.Data = buf.Bytes()
+
+	return result, nil
+}
+
+// opaque reports whether an image has no transparent pixels. Decoders return image types
+// that can tell; anything else is treated as transparent.
+func opaque(img image.Image) bool {
+	if o, ok := img.(interface{ Opaque() bool }); ok {
+		return o.Opaque()
+	}
+	return false
+}
+
+// resize scales img into the box described by opts
+func resize(img image.Image, opts Options) image.Image {
+	if !opts.Resizes() {
+		return img
+	}
+
+	bounds := img.Bounds()
+	srcW, srcH := bounds.Dx(), bounds.Dy()
+	boxW, boxH := opts.Width, opts.Height
+	if boxW == 0 {
+		boxW = max(1, srcW*boxH/srcH)
+	}
+	if boxH == 0 {
+		boxH = max(1, srcH*boxW/srcW)
+	}
+
+	// Never enlarge: shrink the box to the source, keeping its aspect ratio
+	if boxW > srcW || boxH > srcH {
+		scale := min(float64(srcW)/float64(boxW), float64(srcH)/float64(boxH))
+		boxW, boxH = max(1, int(float64(boxW)*scale)), max(1, int(float64(boxH)*scale))
+	}
+
+	srcRect := bounds
+	dstW, dstH := boxW, boxH
+	switch opts.Fit {
+	case FitContain:
+		// Scale down to the limiting side
+		if srcW*boxH > srcH*boxW {
+			dstH = max(1, srcH*boxW/srcW)
+		} else {
+			dstW = max(1, srcW*boxH/srcH)
+		}
+	case FitCover:
+		// Crop the source to the box's aspect ratio, centered
+		if srcW*boxH > srcH*boxW {
+			cropW := srcH * boxW / boxH
+			x0 := bounds.Min.X + (srcW-cropW)/2
+			srcRect = image.Rect(x0, bounds.Min.Y, x0+cropW, bounds.Max.Y)
+		} else {
+			cropH := srcW * boxH / boxW
+			y0 := bounds.Min.Y + (srcH-cropH)/2
+			srcRect = image.Rect(bounds.Min.X, y0, bounds.Max.X, y0+cropH)
+		}
+	}
+
+	dst := image.NewNRGBA(image.Rect(0, 0, dstW, dstH))
+	draw.CatmullRom.Scale(dst, dst.Bounds(), img, srcRect, draw.Src, nil)
+	return dst
+}
@@ -0,0 +1,93 @@
+package imaging
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"testing"
+)
+
+func TestTransformOutputFormat(t *testing.T) {
+	var opaqueJPEG, transparentPNG, opaquePNG bytes.Buffer
+	if err := jpeg.Encode(&opaqueJPEG, fill(400, 200, gradient(400, 200)), nil); err != nil {
+		t.Fatal(err)
+	}
+	if err := png.Encode(&transparentPNG, fill(400, 200, solid(color.NRGBA{255, 0, 0, 128}))); err != nil {
+		t.Fatal(err)
+	}
+	if err := png.Encode(&opaquePNG, fill(400, 200, solid(color.NRGBA{0, 0, 255, 255}))); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name         string
+		source       []byte
+		acceptWebP   bool
+		wantType     string
+		wantLossless bool
+	}{
+		{"JPEG", opaqueJPEG.Bytes(), false, "image/jpeg", false},
+		{"JPEG stays JPEG for WebP clients", opaqueJPEG.Bytes(), true, "image/jpeg", false},
+		{"transparent PNG", transparentPNG.Bytes(), false, "image/png", true},
+		{"transparent PNG as WebP", transparentPNG.Bytes(), true, "image/webp", true},
+		{"opaque PNG as WebP", opaquePNG.Bytes(), true, "image/webp", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := Transform(tt.source, Options{Width: 128, Fit: FitContain, Quality: DefaultQuality}, tt.acceptWebP)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if result.ContentType != tt.wantType || result.Lossless != tt.wantLossless {
+				t.Errorf("got %s (lossless %v), want %s (lossless %v)", result.ContentType, result.Lossless, tt.wantType, tt.wantLossless)
+			}
+
+			config, format, err := image.DecodeConfig(bytes.NewReader(result.Data))
+			if err != nil {
+				t.Fatalf("output does not decode: %v", err)
+			}
+			if "image/"+format != tt.wantType {
+				t.Errorf("output is %s, want %s", format, tt.wantType)
+			}
+			if config.Width != 128 || config.Height != 64 {
+				t.Errorf("output is %dx%d, want 128x64", config.Width, config.Height)
+			}
+		})
+	}
+}
+
+func TestParseOptions(t *testing.T) {
+	tests := []struct {
+		name                   string
+		width, height, quality string
+		fit                    string
+		want                   Options
+		wantErr                bool
+	}{
+		{name: "defaults", want: Options{Fit: FitContain, Quality: DefaultQuality}},
+		{name: "snapped up", width: "300", quality: "75", fit: "cover", want: Options{Width: 384, Fit: FitCover, Quality: 80}},
+		{name: "exact", height: "3840", quality: "100", want: Options{Height: 3840, Fit: FitContain, Quality: 100}},
+		{name: "too wide", width: "3841", wantErr: true},
+		{name: "zero height", height: "0", wantErr: true},
+		{name: "quality above 100", quality: "101", wantErr: true},
+		{name: "unknown fit", fit: "stretch", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseOptions(tt.width, tt.height, tt.fit, tt.quality)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("ParseOptions() = %+v, want an error", got)
+				}
+				return
+			}
+			if err != nil || got != tt.want {
+				t.Errorf("ParseOptions() = %+v, %v, want %+v", got, err, tt.want)
+			}
+		})
+	}
+}
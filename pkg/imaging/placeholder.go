@@ -0,0 +1,198 @@
+package imaging
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"math"
+	"strings"
+
+	"golang.org/x/image/draw"
+)
+
+// placeholderSize is the longest side images are scaled down to before computing placeholders
+const placeholderSize = 32
+
+// Placeholder is a low-quality stand-in shown while an image loads
+type Placeholder struct {
+	BlurHash      string `json:"blurHash"`      // https://blurha.sh, 4x3 components (3x4 for portrait images)
+	DominantColor string `json:"dominantColor"` // #rrggbb; empty for fully transparent images
+}
+
+// NewPlaceholder decodes an image and computes its BlurHash and dominant color.
+// Transparent areas are composited on white, the usual background of logos.
+func NewPlaceholder(source []byte) (*Placeholder, error) {
+	config, _, err := image.DecodeConfig(bytes.NewReader(source))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrUnsupported, err)
+	}
+	if config.Width*config.Height > MaxSourcePixels {
+		return nil, fmt.Errorf("%w: %dx%d source is too large", ErrUnsupported, config.Width, config.Height)
+	}
+	img, _, err := image.Decode(bytes.NewReader(source))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrUnsupported, err)
+	}
+
+	// Both are computed on a tiny copy: the result would be nearly identical at full size
+	bounds := img.Bounds()
+	w, h := placeholderSize, placeholderSize
+	if bounds.Dx() > bounds.Dy() {
+		h = max(1, bounds.Dy()*placeholderSize/bounds.Dx())
+	} else {
+		w = max(1, bounds.Dx()*placeholderSize/bounds.Dy())
+	}
+	small := image.NewNRGBA(image.Rect(0, 0, w, h))
+	draw.BiLinear.Scale(small, small.Bounds(), img, bounds, draw.Src, nil)
+
+	xComponents, yComponents := 4, 3
+	if h > w {
+		xComponents, yComponents = 3, 4
+	}
+	return &Placeholder{
+		BlurHash:      blurHash(small, xComponents, yComponents),
+		DominantColor: dominantColor(small),
+	}, nil
+}
+
+// base83 is the BlurHash alphabet
+const base83 = "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz#$%*+,-.:;=?@[]^_{|}~"
+
+// blurHash encodes img following the reference algorithm (github.com/woltapp/blurhash)
+func blurHash(img *image.NRGBA, xComponents, yComponents int) string {
+	w, h := img.Rect.Dx(), img.Rect.Dy()
+
+	// Linear RGB of every pixel, composited on white
+	linear := make([][3]float64, w*h)
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			c := img.NRGBAAt(x, y)
+			alpha := float64(c.A) / 255
+			for i, v := range [3]uint8{c.R, c.G, c.B} {
+				linear[y*w+x][i] = srgbToLinear(v)*alpha + (1 - alpha)
+			}
+		}
+	}
+
+	factors := make([][3]float64, 0, xComponents*yComponents)
+	for j := 0; j < yComponents; j++ {
+		for i := 0; i < xComponents; i++ {
+			normalisation := 2.0
+			if i == 0 && j == 0 {
+				normalisation = 1
+			}
+			var factor [3]float64
+			for y := 0; y < h; y++ {
+				for x := 0; x < w; x++ {
+					basis := math.Cos(math.Pi*float64(i*x)/float64(w)) * math.Cos(math.Pi*float64(j*y)/float64(h))
+					for k := range factor {
+						factor[k] += basis * linear[y*w+x][k]
+					}
+				}
+			}
+			scale := normalisation / float64(w*h)
+			for k := range factor {
+				factor[k] *= scale
+			}
+			factors = append(factors, factor)
+		}
+	}
+
+	var hash strings.Builder
+	encode83(&hash, (xComponents-1)+(yComponents-1)*9, 1)
+
+	dc, ac := factors[0], factors[1:]
+	maximumValue := 1.0
+	if len(ac) > 0 {
+		actualMaximum := 0.0
+		for _, factor := range ac {
+			for _, v := range factor {
+				actualMaximum = math.Max(actualMaximum, math.Abs(v))
+			}
+		}
+		quantisedMaximum := int(math.Max(0, math.Min(82, math.Floor(actualMaximum*166-0.5))))
+		maximumValue = float64(quantisedMaximum+1) / 166
+		encode83(&hash, quantisedMaximum, 1)
+	} else {
+		encode83(&hash, 0, 1)
+	}
+
+	encode83(&hash, linearToSRGB(dc[0])<<16+linearToSRGB(dc[1])<<8+linearToSRGB(dc[2]), 4)
+	for _, factor := range ac {
+		value := 0
+		for _, v := range factor {
+			quantised := int(math.Max(0, math.Min(18, math.Floor(signPow(v/maximumValue, 0.5)*9+9.5))))
+			value = value*19 + quantised
+		}
+		encode83(&hash, value, 2)
+	}
+	return hash.String()
+}
+
+// dominantColor returns the average of the most common color bucket (4 bits per channel)
+// among the mostly opaque pixels
+func dominantColor(img *image.NRGBA) string {
+	type bucket struct {
+		count   int
+		r, g, b int
+	}
+	buckets := make(map[int]*bucket)
+	var best *bucket
+	for y := img.Rect.Min.Y; y < img.Rect.Max.Y; y++ {
+		for x := img.Rect.Min.X; x < img.Rect.Max.X; x++ {
+			c := img.NRGBAAt(x, y)
+			if c.A < 128 {
+				continue
+			}
+			key := int(c.R>>4)<<8 | int(c.G>>4)<<4 | int(c.B>>4)
+			b := buckets[key]
+			if b == nil {
+				b = &bucket{}
+				buckets[key] = b
+			}
+			b.count++
+			b.r, b.g, b.b = b.r+int(c.R), b.g+int(c.G), b.b+int(c.B)
+			if best == nil || b.count > best.count {
+				best = b
+			}
+		}
+	}
+	if best == nil {
+		return ""
+	}
+	return fmt.Sprintf("#%02x%02x%02x", best.r/best.count, best.g/best.count, best.b/best.count)
+}
+
+// encode83 appends value as length base83 digits
+func encode83(hash *strings.Builder, value, length int) {
+	divisor := 1
+	for i := 1; i < length; i++ {
+		divisor *= 83
+	}
+	for ; divisor > 0; divisor /= 83 {
+		hash.WriteByte(base83[(value/divisor)%83])
+	}
+}
+
+// srgbToLinear converts an sRGB channel to linear light
+func srgbToLinear(value uint8) float64 {
+	v := float64(value) / 255
+	if v <= 0.04045 {
+		return v / 12.92
+	}
+	return math.Pow((v+0.055)/1.055, 2.4)
+}
+
+// linearToSRGB converts linear light to an sRGB channel
+func linearToSRGB(value float64) int {
+	v := math.Max(0, math.Min(1, value))
+	if v <= 0.0031308 {
+		return int(v*12.92*255 + 0.5)
+	}
+	return int((1.055*math.Pow(v, 1/2.4)-0.055)*255 + 0.5)
+}
+
+// signPow raises the magnitude of value to exp, keeping its sign
+func signPow(value, exp float64) float64 {
+	return math.Copysign(math.Pow(math.Abs(value), exp), value)
+}
@@ -0,0 +1,148 @@
+package imaging
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"os"
+	"testing"
+)
+
+// fill returns a w x h image colored by pixel
+func fill(w, h int, pixel func(x, y int) color.NRGBA) *image.NRGBA {
+	img := image.NewNRGBA(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			img.SetNRGBA(x, y, pixel(x, y))
+		}
+	}
+	return img
+}
+
+func solid(c color.NRGBA) func(x, y int) color.NRGBA {
+	return func(x, y int) color.NRGBA { return c }
+}
+
+func gradient(w, h int) func(x, y int) color.NRGBA {
+	return func(x, y int) color.NRGBA {
+		return color.NRGBA{uint8(x * 255 / (w - 1)), uint8(y * 255 / (h - 1)), 128, 255}
+	}
+}
+
+// The expected hashes come from the reference encoder (github.com/woltapp/blurhash, through
+// its Go port github.com/buckket/go-blurhash) run on the same pixels
+func TestBlurHashMatchesReference(t *testing.T) {
+	tests := []struct {
+		name                     string
+		img                      *image.NRGBA
+		xComponents, yComponents int
+		want                     string
+	}{
+		{"white", fill(8, 8, solid(color.NRGBA{255, 255, 255, 255})), 4, 3, "LfTSUA~qfQ~q~qt7fQt7fQfQfQfQ"},
+		{"red", fill(16, 12, solid(color.NRGBA{200, 30, 60, 255})), 4, 3, "LFM^#R]UfQ]U|yo2fQo2fQfQfQfQ"},
+		{"landscape gradient", fill(32, 24, gradient(32, 24)), 4, 3, "L$HewF2swxX8l}WDjte;gJfjfQfj"},
+		{"portrait gradient", fill(24, 32, gradient(24, 32)), 3, 4, "T$HoEv2swxl}WDjtgJfjfQnmWpjt"},
+		{"checkerboard", fill(32, 32, func(x, y int) color.NRGBA {
+			if (x/4+y/4)%2 == 0 {
+				return color.NRGBA{20, 60, 200, 255}
+			}
+			return color.NRGBA{220, 40, 40, 255}
+		}), 4, 3, "L3In3z}KfQ}K}KFhfQFhfQfQfQfQ"},
+		{"dc only", fill(32, 24, gradient(32, 24)), 1, 1, "00HewF"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := blurHash(tt.img, tt.xComponents, tt.yComponents); got != tt.want {
+				t.Errorf("blurHash() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+// testdata/blurhash.png is the test image of github.com/buckket/go-blurhash, whose own
+// tests pin its hash
+func TestBlurHashMatchesReferenceImage(t *testing.T) {
+	f, err := os.Open("testdata/blurhash.png")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	decoded, err := png.Decode(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	img := image.NewNRGBA(decoded.Bounds())
+	draw.Draw(img, img.Bounds(), decoded, decoded.Bounds().Min, draw.Src)
+
+	if got, want := blurHash(img, 4, 3), "LFE.@D9F01_2%L%MIVD*9Goe-;WB"; got != want {
+		t.Errorf("blurHash() = %q, want %q", got, want)
+	}
+}
+
+func TestBlurHashCompositesTransparencyOnWhite(t *testing.T) {
+	transparent := fill(8, 8, solid(color.NRGBA{0, 0, 0, 0}))
+	white := fill(8, 8, solid(color.NRGBA{255, 255, 255, 255}))
+	if got, want := blurHash(transparent, 4, 3), blurHash(white, 4, 3); got != want {
+		t.Errorf("blurHash(transparent) = %q, want the white hash %q", got, want)
+	}
+}
+
+func TestDominantColor(t *testing.T) {
+	red := color.NRGBA{200, 30, 60, 255}
+	tests := []struct {
+		name string
+		img  *image.NRGBA
+		want string
+	}{
+		{"opaque", fill(4, 4, solid(red)), "#c81e3c"},
+		{"fully transparent", fill(4, 4, solid(color.NRGBA{200, 30, 60, 0})), ""},
+		{"mostly transparent", fill(4, 4, func(x, y int) color.NRGBA {
+			if x == 0 {
+				return red
+			}
+			return color.NRGBA{0, 0, 255, 0}
+		}), "#c81e3c"},
+		{"faint pixels ignored", fill(4, 4, func(x, y int) color.NRGBA {
+			if x == 0 {
+				return red
+			}
+			return color.NRGBA{0, 0, 255, 127}
+		}), "#c81e3c"},
+		{"half opaque pixels count", fill(4, 4, func(x, y int) color.NRGBA {
+			if x == 0 {
+				return red
+			}
+			return color.NRGBA{0, 0, 255, 128}
+		}), "#0000ff"},
+		{"bucket average", fill(2, 1, func(x, y int) color.NRGBA {
+			return color.NRGBA{uint8(16 + 2*x), 32, 48, 255}
+		}), "#112030"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := dominantColor(tt.img); got != tt.want {
+				t.Errorf("dominantColor() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewPlaceholderTransparentImage(t *testing.T) {
+	var source bytes.Buffer
+	if err := png.Encode(&source, fill(40, 20, solid(color.NRGBA{0, 0, 0, 0}))); err != nil {
+		t.Fatal(err)
+	}
+
+	placeholder, err := NewPlaceholder(source.Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if placeholder.DominantColor != "" {
+		t.Errorf("DominantColor = %q, want empty", placeholder.DominantColor)
+	}
+	if want := blurHash(fill(32, 16, solid(color.NRGBA{255, 255, 255, 255})), 4, 3); placeholder.BlurHash != want {
+		t.Errorf("BlurHash = %q, want the white hash %q", placeholder.BlurHash, want)
+	}
+}
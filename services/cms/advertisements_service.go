@@ -19,61 +19,6 @@ func NewAdvertisementServiceGraphQL(client *CMSClient) *AdvertisementServiceGrap
 	}
 }
 
-// parseMediaField converts Strapi media field to our MediaField model with URL prefixing
-func (s *AdvertisementServiceGraphQL) parseMediaField(media *strapiMediaField) *models.MediaField {
-	if media == nil {
-		return nil
-	}
-
-	field := &models.MediaField{
-		ID:     media.DocumentID,
-		Width:  media.Width,
-		Height: media.Height,
-		URL:    s.client.PrefixMediaURL(media.URL),
-	}
-
-	// Parse formats if available
-	if media.Formats != nil {
-		formats := &models.MediaFormats{}
-
-		// Helper to parse individual format
-		parseFormat := func(formatData interface{}) *models.MediaFormat {
-			if formatData == nil {
-				return nil
-			}
-			formatMap, ok := formatData.(map[string]interface{})
-			if !ok {
-				return nil
-			}
-			url, _ := formatMap["url"].(string)
-			width, _ := formatMap["width"].(float64)
-			height, _ := formatMap["height"].(float64)
-			return &models.MediaFormat{
-				Width:  int(width),
-				Height: int(height),
-				URL:    s.client.PrefixMediaURL(url),
-			}
-		}
-
-		if thumbnail, ok := media.Formats["thumbnail"]; ok {
-			formats.Thumbnail = parseFormat(thumbnail)
-		}
-		if small, ok := media.Formats["small"]; ok {
-			formats.Small = parseFormat(small)
-		}
-		if medium, ok := media.Formats["medium"]; ok {
-			formats.Medium = parseFormat(medium)
-		}
-		if large, ok := media.Formats["large"]; ok {
-			formats.Large = parseFormat(large)
-		}
-
-		field.Formats = formats
-	}
-
-	return field
-}
-
 // GetAll fetches all advertisements
 func (s *AdvertisementServiceGraphQL) GetAll(ctx context.Context) (models.AdvertisementCollectionResponse, error) {
 	data, err := s.client.ExecuteGraphQL(ctx, GetAdvertisementsQuery, nil)
@@ -81,16 +26,9 @@ func (s *AdvertisementServiceGraphQL) GetAll(ctx context.Context) (models.Advert
 		return nil, fmt.Errorf("failed to fetch advertisements: %w", err)
 	}
 
-	var result struct {
-		Advertisements []struct {
-			DocumentID string            `json:"documentId"`
-			Action     string            `json:"Action"`
-			Banner     *strapiMediaField `json:"Banner"`
-		} `json:"advertisements"`
-	}
-
+	var result getAdvertisementsResult
 	if err := json.Unmarshal(data, &result); err != nil {
-		return nil, fmt.Errorf("failed to unmarshal advertisements: %w", err)
+		return nil, newDecodeError("advertisements", err)
 	}
 
 	ads := make(models.AdvertisementCollectionResponse, len(result.Advertisements))
@@ -98,7 +36,7 @@ func (s *AdvertisementServiceGraphQL) GetAll(ctx context.Context) (models.Advert
 		ads[i] = models.AdvertisementData{
 			ID:     ad.DocumentID,
 			Action: ad.Action,
-			Banner: s.parseMediaField(ad.Banner),
+			Banner: s.client.media.Field(ad.Banner),
 		}
 	}
 
@@ -116,27 +54,20 @@ func (s *AdvertisementServiceGraphQL) GetByID(ctx context.Context, documentId st
 		return nil, fmt.Errorf("failed to fetch advertisement: %w", err)
 	}
 
-	var result struct {
-		Advertisement *struct {
-			DocumentID string            `json:"documentId"`
-			Action     string            `json:"Action"`
-			Banner     *strapiMediaField `json:"Banner"`
-		} `json:"advertisement"`
-	}
-
+	var result getAdvertisementResult
 	if err := json.Unmarshal(data, &result); err != nil {
-		return nil, fmt.Errorf("failed to unmarshal advertisement: %w", err)
+		return nil, newDecodeError("advertisement", err)
 	}
 
 	if result.Advertisement == nil {
-		return nil, fmt.Errorf("advertisement not found")
+		return nil, newNotFoundError("advertisement")
 	}
 
 	return &models.AdvertisementResponse{
 		Data: &models.AdvertisementData{
 			ID:     result.Advertisement.DocumentID,
 			Action: result.Advertisement.Action,
-			Banner: s.parseMediaField(result.Advertisement.Banner),
+			Banner: s.client.media.Field(result.Advertisement.Banner),
 		},
 	}, nil
 }
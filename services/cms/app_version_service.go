@@ -26,25 +26,18 @@ func (s *AppVersionServiceGraphQL) Get(ctx context.Context) (*models.Application
 		return nil, fmt.Errorf("failed to fetch application version: %w", err)
 	}
 
-	var result struct {
-		ApplicationVersion *struct {
-			MobileAppVersion     string `json:"MobileAppVersion"`
-			MobileAppBuildNumber int    `json:"MobileAppBuildNumber"`
-			WebVersion           string `json:"WebVersion"`
-		} `json:"applicationVersion"`
-	}
-
+	var result appVersionResult
 	if err := json.Unmarshal(data, &result); err != nil {
-		return nil, fmt.Errorf("failed to unmarshal application version: %w", err)
+		return nil, newDecodeError("application version", err)
 	}
 
 	if result.ApplicationVersion == nil {
-		return nil, fmt.Errorf("application version not found")
+		return nil, newNotFoundError("application version")
 	}
 
 	return &models.ApplicationVersion{
 		MobileAppVersion:     result.ApplicationVersion.MobileAppVersion,
-		MobileAppBuildNumber: result.ApplicationVersion.MobileAppBuildNumber,
+		MobileAppBuildNumber: intValue(result.ApplicationVersion.MobileAppBuildNumber),
 		WebVersion:           result.ApplicationVersion.WebVersion,
 	}, nil
 }
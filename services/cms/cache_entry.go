@@ -0,0 +1,75 @@
+package cms
+
+import (
+	"api-gateway/services/cms/media"
+	"context"
+	"encoding/json"
+	"sync/atomic"
+	"time"
+)
+
+// cacheEntry wraps a cached GraphQL payload with the time it was fetched.
+// The entry is stored with the hard TTL; once the soft TTL of its cache policy has passed
+// it is still served, but marked stale and refreshed in the background.
+// Freshness is evaluated on read, so policy changes apply to entries already in the cache.
+// The originating request is kept so the entry can be re-warmed after it is invalidated, and
+// the images in the payload so their placeholders can be prefetched without parsing it.
+type cacheEntry struct {
+	Data      json.RawMessage        `json:"data"`
+	StoredAt  time.Time              `json:"storedAt"`
+	Query     string                 `json:"query,omitempty"`
+	Variables map[string]interface{} `json:"variables,omitempty"`
+	Media     []media.FileVersion    `json:"media,omitempty"`
+}
+
+// newCacheEntry builds an entry for data fetched now by the given request
+func newCacheEntry(data json.RawMessage, query string, variables map[string]interface{}, mediaFiles []media.FileVersion) cacheEntry {
+	return cacheEntry{
+		Data:      data,
+		StoredAt:  time.Now(),
+		Query:     query,
+		Variables: variables,
+		Media:     mediaFiles,
+	}
+}
+
+// decodeCacheEntry parses a cached value.
+// Values written before entries were wrapped hold the raw GraphQL data; they are treated as fresh.
+func decodeCacheEntry(raw []byte) cacheEntry {
+	var entry cacheEntry
+	if err := json.Unmarshal(raw, &entry); err != nil || entry.Data == nil || entry.StoredAt.IsZero() {
+		return cacheEntry{Data: raw}
+	}
+	return entry
+}
+
+// IsStale reports whether the entry is older than softTTL
+func (e cacheEntry) IsStale(softTTL time.Duration) bool {
+	return !e.StoredAt.IsZero() && time.Since(e.StoredAt) > softTTL
+}
+
+// StaleTracker records whether any CMS payload used for a request was served stale
+type StaleTracker struct {
+	stale atomic.Bool
+}
+
+// Stale reports whether a stale payload was served
+func (t *StaleTracker) Stale() bool {
+	return t.stale.Load()
+}
+
+type staleTrackerKey struct{}
+
+// WithStaleTracker adds a StaleTracker to the context.
+// ExecuteGraphQL marks it whenever it returns a payload past its soft TTL.
+func WithStaleTracker(ctx context.Context) (context.Context, *StaleTracker) {
+	tracker := &StaleTracker{}
+	return context.WithValue(ctx, staleTrackerKey{}, tracker), tracker
+}
+
+// markStale flags the request's StaleTracker, if any
+func markStale(ctx context.Context) {
+	if tracker, ok := ctx.Value(staleTrackerKey{}).(*StaleTracker); ok {
+		tracker.stale.Store(true)
+	}
+}
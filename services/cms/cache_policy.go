@@ -0,0 +1,83 @@
+package cms
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"time"
+)
+
+// CachePolicy controls how responses of a GraphQL operation are cached
+type CachePolicy struct {
+	TTL      time.Duration // Soft TTL; zero uses the client's DefaultCacheTTL
+	StaleTTL time.Duration // Stale window after TTL during which the entry is served while it refreshes; zero disables it
+	Disabled bool          // Skip the cache entirely (requests are still coalesced)
+}
+
+// DefaultCachePolicies are the built-in per-operation policies, keyed by GraphQL operation name.
+// Operations without an entry use the client's DefaultCacheTTL and StaleCacheTTL.
+var DefaultCachePolicies = map[string]CachePolicy{
+	// A force-update has to reach clients quickly
+	"AppVersion": {TTL: 1 * time.Minute, StaleTTL: 5 * time.Minute},
+	// Marketing changes advertisements several times a day
+	"GetAdvertisements": {TTL: 10 * time.Minute, StaleTTL: 1 * time.Hour},
+	"GetAdvertisement":  {TTL: 10 * time.Minute, StaleTTL: 1 * time.Hour},
+	// Prices change more often than catalog data
+	"GetVariantPricesByBrand":  {TTL: 1 * time.Hour, StaleTTL: 24 * time.Hour},
+	"GetCarVariantsByShowroom": {TTL: 1 * time.Hour, StaleTTL: 24 * time.Hour},
+	"GetCarCatalog":            {TTL: 1 * time.Hour, StaleTTL: 24 * time.Hour},
+	// Every search text is its own entry; keep them short-lived
+	"Search": {TTL: 10 * time.Minute, StaleTTL: 1 * time.Hour},
+}
+
+// operationNamePattern extracts the operation name from a GraphQL document
+var operationNamePattern = regexp.MustCompile(`^\s*(?:query|mutation)\s+([_A-Za-z][_0-9A-Za-z]*)`)
+
+// OperationName returns the name of the GraphQL operation in query, or "" for anonymous operations
+func OperationName(query string) string {
+	match := operationNamePattern.FindStringSubmatch(query)
+	if match == nil {
+		return ""
+	}
+	return match[1]
+}
+
+// ParseCachePolicies parses per-operation cache policies from JSON, e.g.
+//
+//	{"AppVersion": {"ttl": "30s", "stale": "5m"}, "GetShowrooms": {"disabled": true}}
+//
+// Durations use Go duration syntax. An omitted "stale" disables the stale window.
+func ParseCachePolicies(raw string) (map[string]CachePolicy, error) {
+	var entries map[string]struct {
+		TTL      string `json:"ttl"`
+		Stale    string `json:"stale"`
+		Disabled bool   `json:"disabled"`
+	}
+	if err := json.Unmarshal([]byte(raw), &entries); err != nil {
+		return nil, fmt.Errorf("invalid cache policies: %w", err)
+	}
+
+	policies := make(map[string]CachePolicy, len(entries))
+	for operation, entry := range entries {
+		policy := CachePolicy{Disabled: entry.Disabled}
+
+		if entry.TTL != "" {
+			ttl, err := time.ParseDuration(entry.TTL)
+			if err != nil {
+				return nil, fmt.Errorf("invalid ttl for %s: %w", operation, err)
+			}
+			policy.TTL = ttl
+		}
+		if entry.Stale != "" {
+			stale, err := time.ParseDuration(entry.Stale)
+			if err != nil {
+				return nil, fmt.Errorf("invalid stale window for %s: %w", operation, err)
+			}
+			policy.StaleTTL = stale
+		}
+
+		policies[operation] = policy
+	}
+
+	return policies, nil
+}
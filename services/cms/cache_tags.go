@@ -0,0 +1,89 @@
+package cms
+
+import (
+	"encoding/json"
+	"sort"
+	"strings"
+)
+
+// contentTypeFields maps GraphQL field names to the content type of the entries they hold.
+// Connection fields (e.g. brands_connection) resolve through their base name.
+var contentTypeFields = map[string]string{
+	"advertisement":      "advertisement",
+	"advertisements":     "advertisement",
+	"applicationVersion": "applicationVersion",
+	"brand":              "brand",
+	"brands":             "brand",
+	"carModel":           "carModel",
+	"carModels":          "carModel",
+	"car_model":          "carModel",
+	"car_models":         "carModel",
+	"carVariant":         "carVariant",
+	"carVariants":        "carVariant",
+	"car_variants":       "carVariant",
+	"cities":             "city",
+	"city":               "city",
+	"governorate":        "governorate",
+	"governorates":       "governorate",
+	"homeCard":           "homeCard",
+	"homeCards":          "homeCard",
+	"showroom":           "showroom",
+	"showrooms":          "showroom",
+}
+
+// ContentTypeTag returns the tag shared by every cached response containing entries of a content type
+func ContentTypeTag(contentType string) string {
+	return contentType
+}
+
+// DocumentTag returns the tag of cached responses containing a specific entry, e.g. brand:<documentId>
+func DocumentTag(contentType, documentID string) string {
+	return contentType + ":" + documentID
+}
+
+// extractCacheTags walks a GraphQL response and returns the content types and
+// documentIds it contains, e.g. ["brand", "brand:abc123", "carModel", ...]
+func extractCacheTags(data json.RawMessage) []string {
+	var root interface{}
+	if err := json.Unmarshal(data, &root); err != nil {
+		return nil
+	}
+
+	found := make(map[string]struct{})
+	collectCacheTags(root, "", found)
+
+	tags := make([]string, 0, len(found))
+	for tag := range found {
+		tags = append(tags, tag)
+	}
+	sort.Strings(tags)
+	return tags
+}
+
+// collectCacheTags adds tags for value, which holds entries of contentType ("" if unknown)
+func collectCacheTags(value interface{}, contentType string, found map[string]struct{}) {
+	switch v := value.(type) {
+	case []interface{}:
+		for _, item := range v {
+			collectCacheTags(item, contentType, found)
+		}
+	case map[string]interface{}:
+		if contentType != "" {
+			if documentID, ok := v["documentId"].(string); ok && documentID != "" {
+				found[DocumentTag(contentType, documentID)] = struct{}{}
+			}
+		}
+
+		for field, child := range v {
+			childType := ""
+			if mapped, ok := contentTypeFields[strings.TrimSuffix(field, "_connection")]; ok {
+				childType = mapped
+				found[ContentTypeTag(mapped)] = struct{}{}
+			} else if field == "nodes" {
+				// Connection results wrap their entries in nodes
+				childType = contentType
+			}
+			collectCacheTags(child, childType, found)
+		}
+	}
+}
@@ -0,0 +1,232 @@
+package cms
+
+import (
+	"api-gateway/services/cms/models"
+	"api-gateway/services/cms/pricing"
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Car catalog paging limits
+const (
+	catalogDefaultPageSize = 20
+	catalogMaxPageSize     = 100
+)
+
+// catalogSorts maps the catalog's sort parameter to Strapi sort fields. Every sort ends with
+// documentId so that pages are stable.
+var catalogSorts = map[string][]string{
+	"price":  {"Price:asc", "documentId:asc"},
+	"-price": {"Price:desc", "documentId:asc"},
+	"year":   {"Year:asc", "documentId:asc"},
+	"-year":  {"Year:desc", "documentId:asc"},
+	"name":   {"car_model.Name:asc", "Name:asc", "documentId:asc"},
+	"-name":  {"car_model.Name:desc", "Name:desc", "documentId:asc"},
+}
+
+// CarCatalogQuery filters, sorts and pages the car catalog. Zero values don't filter.
+type CarCatalogQuery struct {
+	Brands          []string // Brand documentIds or slugs
+	BodyType        string   // Matched case-insensitively, e.g. suv
+	FuelType        string
+	Year            int
+	PriceMin        int
+	PriceMax        int
+	DownPaymentMax  int
+	InstallmentsMax int
+	Showroom        string // Showroom documentId: only cars it prices
+	Sort            string // One of price, year, name; prefixed with - for descending (default name)
+	Page            int
+	PageSize        int
+}
+
+// ParseCarCatalogQuery parses the query parameters of the car catalog endpoint:
+// brand (comma-separated), bodyType, fuelType, year, priceMin, priceMax, downPaymentMax,
+// installmentsMax, showroom, sort, page and pageSize
+func ParseCarCatalogQuery(params map[string]string) (CarCatalogQuery, error) {
+	query := CarCatalogQuery{
+		BodyType: params["bodyType"],
+		FuelType: params["fuelType"],
+		Showroom: params["showroom"],
+		Sort:     params["sort"],
+		Page:     1,
+		PageSize: catalogDefaultPageSize,
+	}
+	for _, brand := range strings.Split(params["brand"], ",") {
+		if brand = strings.TrimSpace(brand); brand != "" {
+			query.Brands = append(query.Brands, brand)
+		}
+	}
+
+	if query.Sort == "" {
+		query.Sort = "name"
+	}
+	if _, ok := catalogSorts[query.Sort]; !ok {
+		return CarCatalogQuery{}, newBadInputError("sort must be one of price, year or name, optionally prefixed with -")
+	}
+
+	numbers := []struct {
+		param string
+		value *int
+		min   int
+	}{
+		{"year", &query.Year, 1},
+		{"priceMin", &query.PriceMin, 0},
+		{"priceMax", &query.PriceMax, 0},
+		{"downPaymentMax", &query.DownPaymentMax, 0},
+		{"installmentsMax", &query.InstallmentsMax, 0},
+		{"page", &query.Page, 1},
+		{"pageSize", &query.PageSize, 1},
+	}
+	for _, number := range numbers {
+		raw := params[number.param]
+		if raw == "" {
+			continue
+		}
+		value, err := strconv.Atoi(raw)
+		if err != nil || value < number.min {
+			return CarCatalogQuery{}, newBadInputError(fmt.Sprintf("%s must be an integer of at least %d", number.param, number.min))
+		}
+		*number.value = value
+	}
+
+	if query.PageSize > catalogMaxPageSize {
+		return CarCatalogQuery{}, newBadInputError(fmt.Sprintf("pageSize must be at most %d", catalogMaxPageSize))
+	}
+	if query.PriceMax != 0 && query.PriceMax < query.PriceMin {
+		return CarCatalogQuery{}, newBadInputError("priceMax must not be below priceMin")
+	}
+	return query, nil
+}
+
+// filters translates the query into a CarVariantFiltersInput; car model filters are nested
+// under car_model as a CarModelFiltersInput
+func (q CarCatalogQuery) filters() map[string]interface{} {
+	filters := map[string]interface{}{}
+	modelFilters := map[string]interface{}{}
+
+	if len(q.Brands) > 0 {
+		modelFilters["brand"] = map[string]interface{}{
+			"or": []map[string]interface{}{
+				{"documentId": map[string]interface{}{"in": q.Brands}},
+				{"Slug": map[string]interface{}{"in": q.Brands}},
+			},
+		}
+	}
+	if q.BodyType != "" {
+		modelFilters["BodyType"] = map[string]interface{}{"eqi": q.BodyType}
+	}
+	if q.FuelType != "" {
+		modelFilters["FuelType"] = map[string]interface{}{"eqi": q.FuelType}
+	}
+	if len(modelFilters) > 0 {
+		filters["car_model"] = modelFilters
+	}
+
+	if q.Year != 0 {
+		filters["Year"] = map[string]interface{}{"eq": q.Year}
+	}
+	price := map[string]interface{}{}
+	if q.PriceMin != 0 {
+		price["gte"] = q.PriceMin
+	}
+	if q.PriceMax != 0 {
+		price["lte"] = q.PriceMax
+	}
+	if len(price) > 0 {
+		filters["Price"] = price
+	}
+	if q.DownPaymentMax != 0 {
+		filters["MinimumDownPaymet"] = map[string]interface{}{"lte": q.DownPaymentMax}
+	}
+	if q.InstallmentsMax != 0 {
+		filters["MinimumInstallments"] = map[string]interface{}{"lte": q.InstallmentsMax}
+	}
+	if q.Showroom != "" {
+		filters["ShowroomPricing"] = map[string]interface{}{
+			"showroom": map[string]interface{}{"documentId": map[string]interface{}{"eq": q.Showroom}},
+		}
+	}
+	return filters
+}
+
+// GetCatalog fetches one page of the car catalog: the variants matching the query, with
+// their car model and brand. Filtering, sorting and paging all happen in Strapi.
+func (s *CarModelServiceGraphQL) GetCatalog(ctx context.Context, query CarCatalogQuery) (*models.CarCatalogPage, error) {
+	variables := map[string]interface{}{
+		"filters":  query.filters(),
+		"sort":     catalogSorts[query.Sort],
+		"page":     query.Page,
+		"pageSize": query.PageSize,
+	}
+
+	data, err := s.client.ExecuteGraphQL(ctx, GetCarCatalogQuery, variables)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch car catalog: %w", err)
+	}
+
+	var result getCarCatalogResult
+	if err := json.Unmarshal(data, &result); err != nil {
+		return nil, newDecodeError("car catalog", err)
+	}
+
+	page := &models.CarCatalogPage{
+		Data: make([]models.CatalogCar, 0),
+		Pagination: models.Pagination{
+			Page:     query.Page,
+			PageSize: query.PageSize,
+		},
+	}
+	if result.CarVariantsConnection == nil {
+		return page, nil
+	}
+	if pageInfo := result.CarVariantsConnection.PageInfo; pageInfo != nil {
+		page.Pagination = models.Pagination{
+			Page:      pageInfo.Page,
+			PageSize:  pageInfo.PageSize,
+			PageCount: pageInfo.PageCount,
+			Total:     pageInfo.Total,
+		}
+	}
+
+	for _, variant := range result.CarVariantsConnection.Nodes {
+		car := models.CatalogCar{
+			ID:              variant.DocumentID,
+			Title:           variant.DisplayName,
+			Year:            variant.Year,
+			Price:           variant.Price,
+			MinDownPayment:  intValue(variant.MinimumDownPayment),
+			MinInstallments: intValue(variant.MinimumInstallments),
+		}
+		if car.Title == "" {
+			car.Title = variant.Name
+		}
+
+		pricingVariant := pricing.Variant{Price: variant.Price}
+		for _, showroomPricing := range variant.ShowroomPricing {
+			pricingVariant.ShowroomPrices = append(pricingVariant.ShowroomPrices, showroomPricing.Price)
+		}
+
+		if model := variant.CarModel; model != nil {
+			car.BodyType = model.BodyType
+			car.FuelType = model.FuelType
+			car.Model = &models.SimpleCarModelRef{ID: model.DocumentID, Title: model.Name}
+			if len(model.Images) > 0 {
+				car.Thumbnail = s.client.media.Field(&model.Images[0])
+			}
+			pricingVariant.BodyType = model.BodyType
+			if model.Brand != nil {
+				car.Brand = &models.SimpleBrandRef{ID: model.Brand.DocumentID, Title: model.Brand.Name}
+				pricingVariant.BrandID, pricingVariant.BrandSlug = model.Brand.DocumentID, model.Brand.Slug
+			}
+		}
+		car.MarketPrice, car.MarketPriceDerivation = s.client.pricing.MarketPrice(pricingVariant)
+
+		page.Data = append(page.Data, car)
+	}
+
+	return page, nil
+}
@@ -0,0 +1,254 @@
+package cms
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/vektah/gqlparser/v2"
+	"github.com/vektah/gqlparser/v2/ast"
+)
+
+func TestParseCarCatalogQuery(t *testing.T) {
+	tests := []struct {
+		name    string
+		params  map[string]string
+		want    CarCatalogQuery
+		wantErr string
+	}{
+		{
+			name:   "defaults",
+			params: map[string]string{},
+			want:   CarCatalogQuery{Sort: "name", Page: 1, PageSize: 20},
+		},
+		{
+			name: "every parameter",
+			params: map[string]string{
+				"brand": " bmw, ,audi-id ", "bodyType": "suv", "fuelType": "Hybrid", "year": "2024",
+				"priceMin": "1000000", "priceMax": "2000000", "downPaymentMax": "300000", "installmentsMax": "25000",
+				"showroom": "s1", "sort": "-price", "page": "3", "pageSize": "100",
+			},
+			want: CarCatalogQuery{
+				Brands: []string{"bmw", "audi-id"}, BodyType: "suv", FuelType: "Hybrid", Year: 2024,
+				PriceMin: 1000000, PriceMax: 2000000, DownPaymentMax: 300000, InstallmentsMax: 25000,
+				Showroom: "s1", Sort: "-price", Page: 3, PageSize: 100,
+			},
+		},
+		{
+			name:   "priceMax without priceMin",
+			params: map[string]string{"priceMax": "500000"},
+			want:   CarCatalogQuery{PriceMax: 500000, Sort: "name", Page: 1, PageSize: 20},
+		},
+		{name: "unknown sort", params: map[string]string{"sort": "mileage"}, wantErr: "sort must be one of"},
+		{name: "descending unknown sort", params: map[string]string{"sort": "-mileage"}, wantErr: "sort must be one of"},
+		{name: "non-numeric year", params: map[string]string{"year": "new"}, wantErr: "year must be an integer of at least 1"},
+		{name: "negative price", params: map[string]string{"priceMin": "-1"}, wantErr: "priceMin must be an integer of at least 0"},
+		{name: "page zero", params: map[string]string{"page": "0"}, wantErr: "page must be an integer of at least 1"},
+		{name: "page size too large", params: map[string]string{"pageSize": "101"}, wantErr: "pageSize must be at most 100"},
+		{name: "inverted price range", params: map[string]string{"priceMin": "2000", "priceMax": "1000"}, wantErr: "priceMax must not be below priceMin"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseCarCatalogQuery(tt.params)
+			if tt.wantErr != "" {
+				var cmsErr *Error
+				if !errors.As(err, &cmsErr) || cmsErr.Kind != ErrBadInput {
+					t.Fatalf("err = %v, want an ErrBadInput error", err)
+				}
+				if !reflect.DeepEqual(got, CarCatalogQuery{}) {
+					t.Errorf("query = %+v, want the zero value on error", got)
+				}
+				if !strings.HasPrefix(cmsErr.Detail, tt.wantErr) {
+					t.Errorf("detail = %q, want it to start with %q", cmsErr.Detail, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseCarCatalogQuery() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+// jsonValue normalizes a value to what it looks like after a JSON round trip
+func jsonValue(t *testing.T, value interface{}) interface{} {
+	t.Helper()
+	data, err := json.Marshal(value)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var normalized interface{}
+	if err := json.Unmarshal(data, &normalized); err != nil {
+		t.Fatal(err)
+	}
+	return normalized
+}
+
+func TestCarCatalogQueryFilters(t *testing.T) {
+	tests := []struct {
+		name  string
+		query CarCatalogQuery
+		want  string
+	}{
+		{"no filters", CarCatalogQuery{}, `{}`},
+		{
+			name:  "car model filters are nested",
+			query: CarCatalogQuery{Brands: []string{"bmw", "b1"}, BodyType: "suv", FuelType: "diesel"},
+			want: `{"car_model": {
+				"brand": {"or": [{"documentId": {"in": ["bmw", "b1"]}}, {"Slug": {"in": ["bmw", "b1"]}}]},
+				"BodyType": {"eqi": "suv"},
+				"FuelType": {"eqi": "diesel"}
+			}}`,
+		},
+		{
+			name:  "variant filters",
+			query: CarCatalogQuery{Year: 2024, PriceMin: 100, PriceMax: 200, DownPaymentMax: 50, InstallmentsMax: 10, Showroom: "s1"},
+			want: `{
+				"Year": {"eq": 2024},
+				"Price": {"gte": 100, "lte": 200},
+				"MinimumDownPaymet": {"lte": 50},
+				"MinimumInstallments": {"lte": 10},
+				"ShowroomPricing": {"showroom": {"documentId": {"eq": "s1"}}}
+			}`,
+		},
+		{"price floor only", CarCatalogQuery{PriceMin: 100}, `{"Price": {"gte": 100}}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var want interface{}
+			if err := json.Unmarshal([]byte(tt.want), &want); err != nil {
+				t.Fatal(err)
+			}
+			if got := jsonValue(t, tt.query.filters()); !reflect.DeepEqual(got, want) {
+				t.Errorf("filters() = %v, want %v", got, want)
+			}
+		})
+	}
+}
+
+// checkInputFields fails for every key of value that is not a field of the named input type
+func checkInputFields(t *testing.T, schema *ast.Schema, typeName, path string, value interface{}) {
+	t.Helper()
+	switch v := value.(type) {
+	case []interface{}:
+		for _, item := range v {
+			checkInputFields(t, schema, typeName, path+"[]", item)
+		}
+	case map[string]interface{}:
+		definition := schema.Types[typeName]
+		if definition == nil || definition.Kind != ast.InputObject {
+			t.Errorf("%s: %s is not an input object", path, typeName)
+			return
+		}
+		for key, fieldValue := range v {
+			field := definition.Fields.ForName(key)
+			if field == nil {
+				t.Errorf("%s: %s has no field %s", path, typeName, key)
+				continue
+			}
+			checkInputFields(t, schema, field.Type.Name(), path+"."+key, fieldValue)
+		}
+	}
+}
+
+func TestCarCatalogFiltersMatchSchema(t *testing.T) {
+	schemaBytes, err := os.ReadFile("../../schema.gql")
+	if err != nil {
+		t.Fatal(err)
+	}
+	schema, gqlErr := gqlparser.LoadSchema(&ast.Source{Name: "schema.gql", Input: string(schemaBytes)})
+	if gqlErr != nil {
+		t.Fatal(gqlErr)
+	}
+
+	query := CarCatalogQuery{
+		Brands: []string{"bmw"}, BodyType: "suv", FuelType: "diesel", Year: 2024, PriceMin: 1, PriceMax: 2,
+		DownPaymentMax: 3, InstallmentsMax: 4, Showroom: "s1",
+	}
+	checkInputFields(t, schema, "CarVariantFiltersInput", "filters", jsonValue(t, query.filters()))
+}
+
+func TestGetCatalogSendsSortWithDocumentIDTiebreak(t *testing.T) {
+	for sort, want := range map[string][]interface{}{
+		"price": {"Price:asc", "documentId:asc"},
+		"-year": {"Year:desc", "documentId:asc"},
+		"name":  {"car_model.Name:asc", "Name:asc", "documentId:asc"},
+		"-name": {"car_model.Name:desc", "Name:desc", "documentId:asc"},
+	} {
+		t.Run(sort, func(t *testing.T) {
+			var request GraphQLRequest
+			server, _ := newStubCMS(t, func(w http.ResponseWriter, r *http.Request) {
+				json.NewDecoder(r.Body).Decode(&request)
+				w.Write([]byte(`{"data":{"carVariants_connection":{"nodes":[],"pageInfo":{"page":2,"pageSize":10,"pageCount":3,"total":25}}}}`))
+			})
+			client := NewCMSClient(Config{BaseURL: server.URL, DisablePersistedQueries: true})
+
+			query, err := ParseCarCatalogQuery(map[string]string{"sort": sort, "page": "2", "pageSize": "10", "brand": "bmw"})
+			if err != nil {
+				t.Fatal(err)
+			}
+			page, err := NewCarModelServiceGraphQL(client).GetCatalog(context.Background(), query)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if got := request.Variables["sort"]; !reflect.DeepEqual(got, want) {
+				t.Errorf("sort = %v, want %v", got, want)
+			}
+			if request.Variables["page"] != float64(2) || request.Variables["pageSize"] != float64(10) {
+				t.Errorf("page variables = %v, %v, want 2, 10", request.Variables["page"], request.Variables["pageSize"])
+			}
+			if _, ok := request.Variables["filters"].(map[string]interface{})["car_model"]; !ok {
+				t.Errorf("filters = %v, want the brand under car_model", request.Variables["filters"])
+			}
+			if page.Pagination.Total != 25 || page.Pagination.PageCount != 3 || len(page.Data) != 0 {
+				t.Errorf("page = %+v, want 25 results over 3 pages", page)
+			}
+		})
+	}
+}
+
+func TestGetCatalogMapsVariants(t *testing.T) {
+	server, _ := newStubCMS(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"data":{"carVariants_connection":{"nodes":[
+			{"documentId":"v1","Name":"xDrive30i","DisplayName":"","Year":2024,"Price":3000000,"MinimumDownPaymet":600000,"MinimumInstallments":null,"ShowroomPricing":[],
+			 "car_model":{"documentId":"m1","Name":"X3","BodyType":"SUV","FuelType":"Petrol","Images":[],"brand":{"documentId":"b1","Name":"BMW","Slug":"bmw"}}},
+			{"documentId":"v2","Name":"base","DisplayName":"Orphan","Year":2023,"Price":100,"ShowroomPricing":[],"car_model":null}
+		],"pageInfo":{"page":1,"pageSize":20,"pageCount":1,"total":2}}}}`))
+	})
+	client := NewCMSClient(Config{BaseURL: server.URL, DisablePersistedQueries: true})
+
+	page, err := NewCarModelServiceGraphQL(client).GetCatalog(context.Background(), CarCatalogQuery{Sort: "name", Page: 1, PageSize: 20})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(page.Data) != 2 {
+		t.Fatalf("got %d cars, want 2", len(page.Data))
+	}
+
+	car := page.Data[0]
+	if car.ID != "v1" || car.Title != "xDrive30i" || car.Year != 2024 || car.Price != 3000000 {
+		t.Errorf("car = %+v, want v1 titled by its name", car)
+	}
+	if car.MinDownPayment != 600000 || car.MinInstallments != 0 {
+		t.Errorf("payments = %d, %d, want 600000 and 0 for null", car.MinDownPayment, car.MinInstallments)
+	}
+	if car.BodyType != "SUV" || car.Model == nil || car.Model.ID != "m1" || car.Brand == nil || car.Brand.Title != "BMW" {
+		t.Errorf("car = %+v, want its model and brand", car)
+	}
+
+	orphan := page.Data[1]
+	if orphan.Title != "Orphan" || orphan.Model != nil || orphan.Brand != nil {
+		t.Errorf("orphan = %+v, want its display name and no model or brand", orphan)
+	}
+}
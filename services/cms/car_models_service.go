@@ -1,10 +1,15 @@
 package cms
 
 import (
+	"api-gateway/pkg/locale"
 	"api-gateway/services/cms/models"
+	"api-gateway/services/cms/pricing"
+	"api-gateway/services/cms/specs"
 	"context"
 	"encoding/json"
 	"fmt"
+
+	"golang.org/x/sync/errgroup"
 )
 
 // CarModelServiceGraphQL handles operations for car models using GraphQL
@@ -19,161 +24,170 @@ func NewCarModelServiceGraphQL(client *CMSClient) *CarModelServiceGraphQL {
 	}
 }
 
-// parseMediaField converts Strapi media field to our MediaField model with URL prefixing
-func (s *CarModelServiceGraphQL) parseMediaField(media *strapiMediaField) *models.MediaField {
-	if media == nil {
-		return nil
-	}
-
-	field := &models.MediaField{
-		ID:     media.DocumentID,
-		Width:  media.Width,
-		Height: media.Height,
-		URL:    s.client.PrefixMediaURL(media.URL),
-	}
-
-	// Parse formats if available
-	if media.Formats != nil {
-		formats := &models.MediaFormats{}
-
-		// Helper to parse individual format
-		parseFormat := func(formatData interface{}) *models.MediaFormat {
-			if formatData == nil {
-				return nil
-			}
-			formatMap, ok := formatData.(map[string]interface{})
-			if !ok {
-				return nil
-			}
-			url, _ := formatMap["url"].(string)
-			width, _ := formatMap["width"].(float64)
-			height, _ := formatMap["height"].(float64)
-			return &models.MediaFormat{
-				Width:  int(width),
-				Height: int(height),
-				URL:    s.client.PrefixMediaURL(url),
-			}
-		}
-
-		if thumbnail, ok := media.Formats["thumbnail"]; ok {
-			formats.Thumbnail = parseFormat(thumbnail)
-		}
-		if small, ok := media.Formats["small"]; ok {
-			formats.Small = parseFormat(small)
-		}
-		if medium, ok := media.Formats["medium"]; ok {
-			formats.Medium = parseFormat(medium)
-		}
-		if large, ok := media.Formats["large"]; ok {
-			formats.Large = parseFormat(large)
-		}
-
-		field.Formats = formats
-	}
+// Variant prices of a brand are fetched in pages; pages after the first run concurrently
+const (
+	variantPricesPageSize    = 100
+	variantPricesConcurrency = 4
+)
 
-	return field
+// priceRange is the lowest and highest variant list and market price of a car model
+type priceRange struct {
+	from        int
+	to          int
+	marketFrom  int
+	marketTo    int
+	derivations []models.MarketPriceDerivation
 }
 
-// GetByBrandID fetches car models for a specific brand with pricing
+// variantPrice is a variant's price and the car model it belongs to
+type variantPrice = getVariantPricesByBrandCarVariantsConnectionNodes
+
+// GetByBrandID fetches car models for a specific brand with pricing.
+// Models are fetched concurrently with the prices of all the brand's variants, which come
+// from one paginated query; pages after the first are fetched in parallel
+// (variantPricesConcurrency at a time). If any page fails the call fails, so models are never
+// returned without their prices.
 func (s *CarModelServiceGraphQL) GetByBrandID(ctx context.Context, brandDocumentID string) ([]models.SimpleCarModel, error) {
+	// Fetch price ranges while the models are being fetched
+	var priceRanges map[string]priceRange
+	var priceErr error
+	pricesDone := make(chan struct{})
+	go func() {
+		defer close(pricesDone)
+		priceRanges, priceErr = s.getPriceRangesByBrand(ctx, brandDocumentID)
+	}()
+
 	variables := map[string]interface{}{
 		"brandDocumentId": brandDocumentID,
 	}
 
 	data, err := s.client.ExecuteGraphQL(ctx, GetCarModelsByBrandQuery, variables)
+	<-pricesDone
 	if err != nil {
 		return nil, fmt.Errorf("failed to fetch car models: %w", err)
 	}
 
-	var result struct {
-		CarModels []struct {
-			DocumentID string              `json:"documentId"`
-			Name       string              `json:"Name"`
-			Images     []strapiMediaField `json:"Images"`
-		} `json:"carModels"`
+	var result getCarModelsByBrandResult
+	if err := json.Unmarshal(data, &result); err != nil {
+		return nil, newDecodeError("car models", err)
 	}
 
-	if err := json.Unmarshal(data, &result); err != nil {
-		return nil, fmt.Errorf("failed to unmarshal car models: %w", err)
+	if priceErr != nil {
+		return nil, priceErr
 	}
 
-	// For each car model, fetch variants to calculate prices
 	carModels := make([]models.SimpleCarModel, len(result.CarModels))
 	for i, model := range result.CarModels {
-		// Get variants to calculate prices
-		variants, err := s.getVariantsByModel(ctx, model.DocumentID)
-		if err != nil {
-			fmt.Printf("[WARN] Failed to fetch variants for model %s: %v\n", model.DocumentID, err)
-		}
-
-		// Calculate price range
-		priceFrom := 0
-		priceTo := 0
-		if len(variants) > 0 {
-			priceFrom = variants[0].Price
-			priceTo = variants[0].Price
-			for _, v := range variants {
-				if v.Price < priceFrom {
-					priceFrom = v.Price
-				}
-				if v.Price > priceTo {
-					priceTo = v.Price
-				}
-			}
-		}
+		// Models without variants keep a zero price range
+		prices := priceRanges[model.DocumentID]
 
 		// Use first image as thumbnail
 		var thumbnail *models.MediaField
 		if len(model.Images) > 0 {
-			thumbnail = s.parseMediaField(&model.Images[0])
+			thumbnail = s.client.media.Field(&model.Images[0])
 		}
 
 		carModels[i] = models.SimpleCarModel{
 			ID:              model.DocumentID,
 			Title:           model.Name,
 			Thumbnail:       thumbnail,
-			PriceFrom:       priceFrom,
-			PriceTo:         priceTo,
-			MarketPriceFrom: priceFrom + 20000,
-			MarketPriceTo:   priceTo + 20000,
+			PriceFrom:       prices.from,
+			PriceTo:         prices.to,
+			MarketPriceFrom: prices.marketFrom,
+			MarketPriceTo:   prices.marketTo,
+
+			MarketPriceDerivations: prices.derivations,
 		}
 	}
 
 	return carModels, nil
 }
 
-// getVariantsByModel is a helper to fetch variants for price calculation
-func (s *CarModelServiceGraphQL) getVariantsByModel(ctx context.Context, carModelDocumentID string) ([]struct {
-	Price int `json:"Price"`
-}, error) {
-	query := `
-		query GetVariantPrices($carModelDocumentId: ID!) {
-			carVariants(filters: { car_model: { documentId: { eq: $carModelDocumentId } } }) {
-				Price
-			}
+// getPriceRangesByBrand returns the price range of every car model of a brand, keyed by
+// car model documentId. All variants of the brand are fetched with one query per page.
+func (s *CarModelServiceGraphQL) getPriceRangesByBrand(ctx context.Context, brandDocumentID string) (map[string]priceRange, error) {
+	nodes, pageCount, err := s.getVariantPricesPage(ctx, brandDocumentID, 1)
+	if err != nil {
+		return nil, err
+	}
+
+	// Large brands span several pages; fetch the rest concurrently
+	if pageCount > 1 {
+		pages := make([][]variantPrice, pageCount-1)
+		group, groupCtx := errgroup.WithContext(ctx)
+		group.SetLimit(variantPricesConcurrency)
+		for page := 2; page <= pageCount; page++ {
+			group.Go(func() error {
+				pageNodes, _, err := s.getVariantPricesPage(groupCtx, brandDocumentID, page)
+				pages[page-2] = pageNodes
+				return err
+			})
+		}
+		if err := group.Wait(); err != nil {
+			return nil, err
+		}
+		for _, pageNodes := range pages {
+			nodes = append(nodes, pageNodes...)
 		}
-	`
+	}
 
-	variables := map[string]interface{}{
-		"carModelDocumentId": carModelDocumentID,
+	// Group the variants by car model
+	modelVariants := make(map[string][]pricing.Variant)
+	for _, variant := range nodes {
+		if variant.CarModel == nil {
+			continue
+		}
+
+		pricingVariant := pricing.Variant{
+			Price:    variant.Price,
+			BrandID:  brandDocumentID,
+			BodyType: variant.CarModel.BodyType,
+		}
+		if variant.CarModel.Brand != nil {
+			pricingVariant.BrandSlug = variant.CarModel.Brand.Slug
+		}
+		for _, showroomPricing := range variant.ShowroomPricing {
+			pricingVariant.ShowroomPrices = append(pricingVariant.ShowroomPrices, showroomPricing.Price)
+		}
+		modelVariants[variant.CarModel.DocumentID] = append(modelVariants[variant.CarModel.DocumentID], pricingVariant)
 	}
 
-	data, err := s.client.ExecuteGraphQL(ctx, query, variables)
-	if err != nil {
-		return nil, err
+	ranges := make(map[string]priceRange, len(modelVariants))
+	for modelID, variants := range modelVariants {
+		prices := priceRange{from: variants[0].Price, to: variants[0].Price}
+		for _, variant := range variants {
+			prices.from = min(prices.from, variant.Price)
+			prices.to = max(prices.to, variant.Price)
+		}
+		prices.marketFrom, prices.marketTo, prices.derivations = s.client.pricing.Range(variants)
+		ranges[modelID] = prices
 	}
 
-	var result struct {
-		CarVariants []struct {
-			Price int `json:"Price"`
-		} `json:"carVariants"`
+	return ranges, nil
+}
+
+// getVariantPricesPage fetches one page of a brand's variant prices and the total page count
+func (s *CarModelServiceGraphQL) getVariantPricesPage(ctx context.Context, brandDocumentID string, page int) ([]variantPrice, int, error) {
+	variables := map[string]interface{}{
+		"brandDocumentId": brandDocumentID,
+		"page":            page,
+		"pageSize":        variantPricesPageSize,
+	}
+
+	data, err := s.client.ExecuteGraphQL(ctx, GetVariantPricesByBrandQuery, variables)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to fetch variant prices: %w", err)
 	}
 
+	var result getVariantPricesByBrandResult
 	if err := json.Unmarshal(data, &result); err != nil {
-		return nil, err
+		return nil, 0, newDecodeError("variant prices", err)
 	}
 
-	return result.CarVariants, nil
+	if result.CarVariantsConnection == nil {
+		return nil, 0, nil
+	}
+	return result.CarVariantsConnection.Nodes, result.CarVariantsConnection.PageInfo.PageCount, nil
 }
 
 // GetDetailedByID fetches a detailed car model with all variants and showrooms
@@ -188,30 +202,17 @@ func (s *CarModelServiceGraphQL) GetDetailedByID(ctx context.Context, carModelDo
 		return nil, fmt.Errorf("failed to fetch car model: %w", err)
 	}
 
-	var modelResult struct {
-		CarModel *struct {
-			DocumentID string              `json:"documentId"`
-			Name       string              `json:"Name"`
-			Images     []strapiMediaField `json:"Images"`
-		} `json:"carModel"`
-	}
-
+	var modelResult getCarModelResult
 	if err := json.Unmarshal(modelData, &modelResult); err != nil {
-		return nil, fmt.Errorf("failed to unmarshal car model: %w", err)
+		return nil, newDecodeError("car model", err)
 	}
 
 	if modelResult.CarModel == nil {
-		return nil, fmt.Errorf("car model not found")
+		return nil, newNotFoundError("car model")
 	}
 
 	// Convert images
-	images := make(models.MediaCollectionField, len(modelResult.CarModel.Images))
-	for i, img := range modelResult.CarModel.Images {
-		parsed := s.parseMediaField(&img)
-		if parsed != nil {
-			images[i] = *parsed
-		}
-	}
+	images := s.client.media.Collection(modelResult.CarModel.Images)
 
 	// Fetch variants with showrooms
 	variantVars := map[string]interface{}{
@@ -223,33 +224,9 @@ func (s *CarModelServiceGraphQL) GetDetailedByID(ctx context.Context, carModelDo
 		return nil, fmt.Errorf("failed to fetch variants: %w", err)
 	}
 
-	var variantResult struct {
-		CarVariants []struct {
-			DocumentID          string `json:"documentId"`
-			Name                string `json:"Name"`
-			Price               int    `json:"Price"`
-			Year                int    `json:"Year"`
-			BrochureURL         string `json:"BrochureURL"`
-			ReviewLink          string `json:"ReviewLink"`
-			Warranty            string `json:"Warranty"`
-			MinimumDownPayment  int    `json:"MinimumDownPaymet"`
-			MinimumInstallments int    `json:"MinimumInstallments"`
-			Specs               *struct {
-				Motor string `json:"Motor"`
-			} `json:"Specs"`
-			ShowroomPricing []struct {
-				Price    int `json:"Price"`
-				Showroom *struct {
-					DocumentID string            `json:"documentId"`
-					Name       string            `json:"Name"`
-					Logo       *strapiMediaField `json:"Logo"`
-				} `json:"showroom"`
-			} `json:"ShowroomPricing"`
-		} `json:"carVariants"`
-	}
-
+	var variantResult getCarVariantsResult
 	if err := json.Unmarshal(variantData, &variantResult); err != nil {
-		return nil, fmt.Errorf("failed to unmarshal variants: %w", err)
+		return nil, newDecodeError("variants", err)
 	}
 
 	// Build detailed response
@@ -274,6 +251,12 @@ func (s *CarModelServiceGraphQL) GetDetailedByID(ctx context.Context, carModelDo
 	minInstallments := 0
 	warranty := ""
 
+	var brandID, brandSlug string
+	if modelResult.CarModel.Brand != nil {
+		brandID, brandSlug = modelResult.CarModel.Brand.DocumentID, modelResult.CarModel.Brand.Slug
+	}
+	pricingVariants := make([]pricing.Variant, 0, len(variantResult.CarVariants))
+
 	showroomMap := make(map[string]*models.SimpleShowroom)
 	reviewMap := make(map[string]*models.ReviewItem)
 	catalogMap := make(map[string]*models.CatalogItem)
@@ -290,14 +273,14 @@ func (s *CarModelServiceGraphQL) GetDetailedByID(ctx context.Context, carModelDo
 		}
 
 		// Update min down payment and installments
-		if variant.MinimumDownPayment > 0 {
-			if minDownPayment == 0 || variant.MinimumDownPayment < minDownPayment {
-				minDownPayment = variant.MinimumDownPayment
+		if downPayment := intValue(variant.MinimumDownPayment); downPayment > 0 {
+			if minDownPayment == 0 || downPayment < minDownPayment {
+				minDownPayment = downPayment
 			}
 		}
-		if variant.MinimumInstallments > 0 {
-			if minInstallments == 0 || variant.MinimumInstallments < minInstallments {
-				minInstallments = variant.MinimumInstallments
+		if installments := intValue(variant.MinimumInstallments); installments > 0 {
+			if minInstallments == 0 || installments < minInstallments {
+				minInstallments = installments
 			}
 		}
 
@@ -320,17 +303,28 @@ func (s *CarModelServiceGraphQL) GetDetailedByID(ctx context.Context, carModelDo
 			Price: variant.Price,
 		})
 
+		pricingVariant := pricing.Variant{
+			Price:     variant.Price,
+			BrandID:   brandID,
+			BrandSlug: brandSlug,
+			BodyType:  modelResult.CarModel.BodyType,
+		}
+		for _, showroomPricing := range variant.ShowroomPricing {
+			pricingVariant.ShowroomPrices = append(pricingVariant.ShowroomPrices, showroomPricing.Price)
+		}
+		pricingVariants = append(pricingVariants, pricingVariant)
+
 		// Process showrooms
 		for _, pricing := range variant.ShowroomPricing {
 			if pricing.Showroom != nil {
 				showroomDocID := pricing.Showroom.DocumentID
-				thumbnail := s.parseMediaField(pricing.Showroom.Logo)
+				thumbnail := s.client.media.Field(pricing.Showroom.Logo)
 
 				if existing, exists := showroomMap[showroomDocID]; exists {
 					if pricing.Price < existing.Price {
 						existing.Price = pricing.Price
-						existing.MinDownPayment = variant.MinimumDownPayment
-						existing.MinInstallments = variant.MinimumInstallments
+						existing.MinDownPayment = intValue(variant.MinimumDownPayment)
+						existing.MinInstallments = intValue(variant.MinimumInstallments)
 					}
 				} else {
 					showroomMap[showroomDocID] = &models.SimpleShowroom{
@@ -338,8 +332,8 @@ func (s *CarModelServiceGraphQL) GetDetailedByID(ctx context.Context, carModelDo
 						Title:           pricing.Showroom.Name,
 						Thumbnail:       thumbnail,
 						Price:           pricing.Price,
-						MinDownPayment:  variant.MinimumDownPayment,
-						MinInstallments: variant.MinimumInstallments,
+						MinDownPayment:  intValue(variant.MinimumDownPayment),
+						MinInstallments: intValue(variant.MinimumInstallments),
 					}
 				}
 			}
@@ -361,7 +355,7 @@ func (s *CarModelServiceGraphQL) GetDetailedByID(ctx context.Context, carModelDo
 			if _, exists := catalogMap[variant.BrochureURL]; !exists {
 				catalogMap[variant.BrochureURL] = &models.CatalogItem{
 					ID:          catalogIDCounter,
-					DownloadURL: variant.BrochureURL,
+					DownloadURL: s.client.media.RewriteURL(variant.BrochureURL),
 				}
 				catalogIDCounter++
 			}
@@ -371,8 +365,7 @@ func (s *CarModelServiceGraphQL) GetDetailedByID(ctx context.Context, carModelDo
 	// Set calculated values
 	detailedModel.PriceFrom = priceFrom
 	detailedModel.PriceTo = priceTo
-	detailedModel.MarketPriceFrom = priceFrom + 20000
-	detailedModel.MarketPriceTo = priceTo + 20000
+	detailedModel.MarketPriceFrom, detailedModel.MarketPriceTo, detailedModel.MarketPriceDerivations = s.client.pricing.Range(pricingVariants)
 	detailedModel.MinDownPayment = minDownPayment
 	detailedModel.MinInstallments = minInstallments
 	detailedModel.Warranty = warranty
@@ -402,61 +395,13 @@ func (s *CarModelServiceGraphQL) GetVariantByID(ctx context.Context, variantDocu
 		return nil, fmt.Errorf("failed to fetch variant: %w", err)
 	}
 
-	var result struct {
-		CarVariant *struct {
-			DocumentID          string `json:"documentId"`
-			Name                string `json:"Name"`
-			Price               int    `json:"Price"`
-			Year                int    `json:"Year"`
-			BrochureURL         string `json:"BrochureURL"`
-			ReviewLink          string `json:"ReviewLink"`
-			Warranty            string `json:"Warranty"`
-			MinimumDownPayment  int    `json:"MinimumDownPaymet"`
-			MinimumInstallments int    `json:"MinimumInstallments"`
-			CarModel            *struct {
-				DocumentID string              `json:"documentId"`
-				Name       string              `json:"Name"`
-				Images     []strapiMediaField `json:"Images"`
-			} `json:"car_model"`
-			Specs *struct {
-				Motor               interface{} `json:"Motor"`
-				Transmission        interface{} `json:"Transmission"`
-				Acceleration        interface{} `json:"Acceleration"`
-				AssembledIn         interface{} `json:"AssembledIn"`
-				GroundClearanceInMM interface{} `json:"GroundClearanceInMM"`
-				HeightInMM          interface{} `json:"HeightInMM"`
-				Horsepower          interface{} `json:"Horsepower"`
-				LengthInMM          interface{} `json:"LengthInMM"`
-				LiterPerKM          interface{} `json:"LiterPerKM"`
-				MaxSpeed            interface{} `json:"MaxSpeed"`
-				Origin              interface{} `json:"Origin"`
-				Speed               interface{} `json:"Speed"`
-				TractionType        interface{} `json:"TractionType"`
-				TrunkSize           interface{} `json:"TrunkSize"`
-				WheelBase           interface{} `json:"WheelBase"`
-				WidthInMM           interface{} `json:"WidthInMM"`
-				Seats               interface{} `json:"Seats"`
-			} `json:"Specs"`
-			Features        interface{} `json:"Features"`
-			ShowroomPricing []struct {
-				Price               int `json:"Price"`
-				MinimuDownpayment   int `json:"MinimuDownpayment"`
-				MinimumInstallements int `json:"MinimumInstallements"`
-				Showroom            *struct {
-					DocumentID string            `json:"documentId"`
-					Name       string            `json:"Name"`
-					Logo       *strapiMediaField `json:"Logo"`
-				} `json:"showroom"`
-			} `json:"ShowroomPricing"`
-		} `json:"carVariant"`
-	}
-
+	var result getCarVariantResult
 	if err := json.Unmarshal(data, &result); err != nil {
-		return nil, fmt.Errorf("failed to unmarshal variant: %w", err)
+		return nil, newDecodeError("variant", err)
 	}
 
 	if result.CarVariant == nil {
-		return nil, fmt.Errorf("variant not found")
+		return nil, newNotFoundError("variant")
 	}
 
 	variant := result.CarVariant
@@ -467,16 +412,30 @@ func (s *CarModelServiceGraphQL) GetVariantByID(ctx context.Context, variantDocu
 		Title:           variant.Name,
 		PriceFrom:       variant.Price,
 		PriceTo:         variant.Price,
-		MarketPriceFrom: variant.Price + 20000,
-		MarketPriceTo:   variant.Price + 20000,
-		MinDownPayment:  variant.MinimumDownPayment,
-		MinInstallments: variant.MinimumInstallments,
+		MinDownPayment:  intValue(variant.MinimumDownPayment),
+		MinInstallments: intValue(variant.MinimumInstallments),
 		Warranty:        variant.Warranty,
 		Showrooms:       make([]models.SimpleShowroom, 0),
 		Specs:           make([]models.SpecItem, 0),
 		Features:        make([]models.FeatureItem, 0),
 	}
 
+	// Derive the market price from the pricing rules
+	pricingVariant := pricing.Variant{Price: variant.Price}
+	if variant.CarModel != nil {
+		pricingVariant.BodyType = variant.CarModel.BodyType
+		if variant.CarModel.Brand != nil {
+			pricingVariant.BrandID, pricingVariant.BrandSlug = variant.CarModel.Brand.DocumentID, variant.CarModel.Brand.Slug
+		}
+	}
+	for _, showroomPricing := range variant.ShowroomPricing {
+		pricingVariant.ShowroomPrices = append(pricingVariant.ShowroomPrices, showroomPricing.Price)
+	}
+	marketPrice, derivation := s.client.pricing.MarketPrice(pricingVariant)
+	detailedVariant.MarketPriceFrom = marketPrice
+	detailedVariant.MarketPriceTo = marketPrice
+	detailedVariant.MarketPriceDerivations = []models.MarketPriceDerivation{derivation}
+
 	// Set car model reference
 	if variant.CarModel != nil {
 		detailedVariant.Model = &models.SimpleCarModelRef{
@@ -486,13 +445,7 @@ func (s *CarModelServiceGraphQL) GetVariantByID(ctx context.Context, variantDocu
 
 		// Use car model images for variant
 		if len(variant.CarModel.Images) > 0 {
-			images := make(models.MediaCollectionField, len(variant.CarModel.Images))
-			for i, img := range variant.CarModel.Images {
-				parsed := s.parseMediaField(&img)
-				if parsed != nil {
-					images[i] = *parsed
-				}
-			}
+			images := s.client.media.Collection(variant.CarModel.Images)
 			detailedVariant.Images = &images
 		}
 	}
@@ -509,7 +462,7 @@ func (s *CarModelServiceGraphQL) GetVariantByID(ctx context.Context, variantDocu
 	if variant.BrochureURL != "" {
 		detailedVariant.Catalog = &models.CatalogItem{
 			ID:          1,
-			DownloadURL: variant.BrochureURL,
+			DownloadURL: s.client.media.RewriteURL(variant.BrochureURL),
 		}
 	}
 
@@ -518,16 +471,16 @@ func (s *CarModelServiceGraphQL) GetVariantByID(ctx context.Context, variantDocu
 	for _, pricing := range variant.ShowroomPricing {
 		if pricing.Showroom != nil {
 			showroomDocID := pricing.Showroom.DocumentID
-			thumbnail := s.parseMediaField(pricing.Showroom.Logo)
+			thumbnail := s.client.media.Field(pricing.Showroom.Logo)
 
 			// Use showroom-specific pricing if available, otherwise fall back to variant pricing
-			minDownPayment := pricing.MinimuDownpayment
-			if minDownPayment == 0 {
-				minDownPayment = variant.MinimumDownPayment
+			minDownPayment := intValue(pricing.MinimumDownPayment)
+			if pricing.MinimumDownPayment == nil {
+				minDownPayment = intValue(variant.MinimumDownPayment)
 			}
-			minInstallments := pricing.MinimumInstallements
-			if minInstallments == 0 {
-				minInstallments = variant.MinimumInstallments
+			minInstallments := intValue(pricing.MinimumInstallments)
+			if pricing.MinimumInstallments == nil {
+				minInstallments = intValue(variant.MinimumInstallments)
 			}
 
 			if existing, exists := showroomMap[showroomDocID]; exists {
@@ -554,59 +507,9 @@ func (s *CarModelServiceGraphQL) GetVariantByID(ctx context.Context, variantDocu
 		detailedVariant.Showrooms = append(detailedVariant.Showrooms, *showroom)
 	}
 
-	// Process specs
+	// Process specs: labels, units and formatting come from the spec catalog
 	if variant.Specs != nil {
-		specID := 1
-
-		// Helper to convert interface{} to string and add spec if not empty
-		addSpec := func(label string, value interface{}) {
-			if value == nil {
-				return
-			}
-			var strValue string
-			switch v := value.(type) {
-			case string:
-				strValue = v
-			case int:
-				strValue = fmt.Sprintf("%d", v)
-			case float64:
-				// Remove unnecessary decimal places
-				if v == float64(int(v)) {
-					strValue = fmt.Sprintf("%d", int(v))
-				} else {
-					strValue = fmt.Sprintf("%.2f", v)
-				}
-			default:
-				strValue = fmt.Sprintf("%v", v)
-			}
-
-			if strValue != "" {
-				detailedVariant.Specs = append(detailedVariant.Specs, models.SpecItem{
-					ID:    specID,
-					Label: label,
-					Value: strValue,
-				})
-				specID++
-			}
-		}
-
-		addSpec("Motor", variant.Specs.Motor)
-		addSpec("Transmission", variant.Specs.Transmission)
-		addSpec("Acceleration", variant.Specs.Acceleration)
-		addSpec("AssembledIn", variant.Specs.AssembledIn)
-		addSpec("GroundClearanceInMM", variant.Specs.GroundClearanceInMM)
-		addSpec("HeightInMM", variant.Specs.HeightInMM)
-		addSpec("Horsepower", variant.Specs.Horsepower)
-		addSpec("LengthInMM", variant.Specs.LengthInMM)
-		addSpec("LiterPerKM", variant.Specs.LiterPerKM)
-		addSpec("MaxSpeed", variant.Specs.MaxSpeed)
-		addSpec("Origin", variant.Specs.Origin)
-		addSpec("Speed", variant.Specs.Speed)
-		addSpec("TractionType", variant.Specs.TractionType)
-		addSpec("TrunkSize", variant.Specs.TrunkSize)
-		addSpec("WheelBase", variant.Specs.WheelBase)
-		addSpec("WidthInMM", variant.Specs.WidthInMM)
-		addSpec("Seats", variant.Specs.Seats)
+		detailedVariant.Specs = specs.Items(variantSpecCatalog, variant.Specs, locale.FromContext(ctx))
 	}
 
 	// Process features
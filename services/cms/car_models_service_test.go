@@ -0,0 +1,108 @@
+package cms
+
+import (
+	"api-gateway/services/cms/models"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// brandPricesStub serves the car models of a brand and its variant prices over pageCount pages
+type brandPricesStub struct {
+	pageCount  int
+	failPage   int // Page answered with 503, 0 for none
+	mu         sync.Mutex
+	pages      []int
+	inflight   atomic.Int64
+	maxPending atomic.Int64
+}
+
+func (s *brandPricesStub) handle(w http.ResponseWriter, r *http.Request) {
+	var request GraphQLRequest
+	json.NewDecoder(r.Body).Decode(&request)
+
+	if strings.Contains(request.Query, "query GetCarModelsByBrand") {
+		w.Write([]byte(`{"data":{"carModels":[
+			{"documentId":"m1","Name":"X3","Images":[]},
+			{"documentId":"m2","Name":"X5","Images":[]},
+			{"documentId":"m3","Name":"X7","Images":[]}
+		]}}`))
+		return
+	}
+
+	page := int(request.Variables["page"].(float64))
+	s.mu.Lock()
+	s.pages = append(s.pages, page)
+	s.mu.Unlock()
+
+	pending := s.inflight.Add(1)
+	defer s.inflight.Add(-1)
+	for {
+		highest := s.maxPending.Load()
+		if pending <= highest || s.maxPending.CompareAndSwap(highest, pending) {
+			break
+		}
+	}
+	time.Sleep(10 * time.Millisecond)
+
+	if page == s.failPage {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		return
+	}
+
+	// Every page has a variant of m1 priced by page; m2 only appears on the last page
+	nodes := []string{fmt.Sprintf(`{"documentId":"v1-%d","Price":%d,"ShowroomPricing":[],"car_model":{"documentId":"m1","BodyType":"SUV","brand":{"documentId":"bmw","Slug":"bmw"}}}`, page, page*100_000)}
+	if page == s.pageCount {
+		nodes = append(nodes, `{"documentId":"v2","Price":50000,"ShowroomPricing":[{"Price":60000}],"car_model":{"documentId":"m2","BodyType":"SUV","brand":null}}`)
+	}
+	fmt.Fprintf(w, `{"data":{"carVariants_connection":{"nodes":[%s],"pageInfo":{"page":%d,"pageCount":%d}}}}`, strings.Join(nodes, ","), page, s.pageCount)
+}
+
+func TestGetByBrandIDFetchesEveryPricePage(t *testing.T) {
+	stub := &brandPricesStub{pageCount: 7}
+	server, _ := newStubCMS(t, stub.handle)
+	client := NewCMSClient(Config{BaseURL: server.URL, DisablePersistedQueries: true})
+
+	carModels, err := NewCarModelServiceGraphQL(client).GetByBrandID(context.Background(), "bmw")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	slices.Sort(stub.pages)
+	if want := []int{1, 2, 3, 4, 5, 6, 7}; !reflect.DeepEqual(stub.pages, want) {
+		t.Errorf("pages requested = %v, want %v", stub.pages, want)
+	}
+	if pending := stub.maxPending.Load(); pending < 2 || pending > variantPricesConcurrency {
+		t.Errorf("%d pages fetched at once, want between 2 and %d", pending, variantPricesConcurrency)
+	}
+
+	absolute := []models.MarketPriceDerivation{{Method: "absolute", Value: 20000}}
+	want := []models.SimpleCarModel{
+		{ID: "m1", Title: "X3", PriceFrom: 100_000, PriceTo: 700_000, MarketPriceFrom: 120_000, MarketPriceTo: 720_000, MarketPriceDerivations: absolute},
+		{ID: "m2", Title: "X5", PriceFrom: 50_000, PriceTo: 50_000, MarketPriceFrom: 70_000, MarketPriceTo: 70_000, MarketPriceDerivations: absolute},
+		{ID: "m3", Title: "X7"},
+	}
+	if !reflect.DeepEqual(carModels, want) {
+		t.Errorf("GetByBrandID() =\n%+v\nwant\n%+v", carModels, want)
+	}
+}
+
+func TestGetByBrandIDFailsWhenAPricePageFails(t *testing.T) {
+	stub := &brandPricesStub{pageCount: 3, failPage: 2}
+	server, _ := newStubCMS(t, stub.handle)
+	client := NewCMSClient(Config{BaseURL: server.URL, Retry: RetryConfig{MaxRetries: -1}, DisablePersistedQueries: true})
+
+	carModels, err := NewCarModelServiceGraphQL(client).GetByBrandID(context.Background(), "bmw")
+	if !errors.Is(err, ErrUnavailable) || carModels != nil {
+		t.Errorf("GetByBrandID() = %v, %v; want no models and ErrUnavailable", carModels, err)
+	}
+}
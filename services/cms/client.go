@@ -3,35 +3,70 @@ package cms
 import (
 	"api-gateway/pkg/cache"
 	"api-gateway/pkg/locale"
+	"api-gateway/services/cms/media"
+	"api-gateway/services/cms/pricing"
 	"bytes"
 	"context"
 	"crypto/sha256"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"net"
 	"net/http"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
+
+	"golang.org/x/sync/singleflight"
 )
 
 // CMSClient is the main client for interacting with Strapi CMS via GraphQL
 type CMSClient struct {
-	baseURL      string
-	mediaBaseURL string // Base URL for media files (without /api)
-	token        string
-	httpClient   *http.Client
-	cache        cache.Cache
-	defaultTTL   time.Duration
+	baseURL    string
+	media      *media.Transformer
+	pricing    pricing.Rules
+	token      string
+	httpClient *http.Client
+	cache      cache.Cache
+	defaultTTL time.Duration // Soft TTL: how long a cached payload is considered fresh
+	staleTTL   time.Duration // How long a payload may still be served after it goes stale
+	policies   map[string]CachePolicy
+	retry      RetryConfig
+	breaker    *circuitBreaker
+
+	// persistedQueries is cleared if Strapi turns out not to support them
+	persistedQueries atomic.Bool
+
+	// inflight coalesces concurrent cache misses for the same key into one upstream request
+	inflight          singleflight.Group
+	refreshing        sync.Map // cache keys with a background refresh in progress
+	upstreamRequests  atomic.Uint64
+	coalescedRequests atomic.Uint64
+	staleServed       atomic.Uint64
+	refreshFailures   atomic.Uint64
+	retries           atomic.Uint64
+	breakerRejections atomic.Uint64
 }
 
 // Config holds configuration for the CMS client
 type Config struct {
 	BaseURL         string
-	MediaBaseURL    string // Optional: base URL for media (if different from BaseURL)
+	MediaBaseURL    string            // Optional: base URL for media (if different from BaseURL)
+	MediaRewriter   media.URLRewriter // Optional: rewrites media URLs; defaults to prefixing MediaBaseURL
 	Token           string
 	RequestTimeout  time.Duration
 	Cache           cache.Cache
-	DefaultCacheTTL time.Duration
+	DefaultCacheTTL time.Duration          // Soft TTL for cached responses
+	StaleCacheTTL   time.Duration          // Stale window after DefaultCacheTTL; entries expire after both have passed
+	CachePolicies   map[string]CachePolicy // Optional: per-operation overrides, merged over DefaultCachePolicies
+	Retry           RetryConfig            // Retries of transient failures (connection errors, 502/503/504)
+	Breaker         BreakerConfig          // Circuit breaker that fails fast while the CMS is unhealthy
+	PricingRules    pricing.Rules          // Optional: market price rules; defaults to pricing.DefaultRules
+
+	DisablePersistedQueries bool // Send full queries instead of registered operations' name and hash
+	MediaPlaceholders       bool // Include BlurHash placeholders and dominant colors of images, computed in the background
 }
 
 // NewCMSClient creates a new CMS client with the given configuration
@@ -42,20 +77,48 @@ func NewCMSClient(config Config) *CMSClient {
 	if config.DefaultCacheTTL == 0 {
 		config.DefaultCacheTTL = 5 * time.Minute
 	}
+	if config.StaleCacheTTL == 0 {
+		config.StaleCacheTTL = config.DefaultCacheTTL
+	}
 	if config.Cache == nil {
 		config.Cache = &cache.NoOpCache{}
 	}
+	if config.PricingRules == nil {
+		config.PricingRules = pricing.DefaultRules
+	}
 
-	// If MediaBaseURL is not provided, derive it from BaseURL by removing /api suffix
-	mediaBaseURL := config.MediaBaseURL
-	if mediaBaseURL == "" {
-		mediaBaseURL = strings.TrimSuffix(config.BaseURL, "/graphql")
+	// Merge configured policies over the built-in ones
+	policies := make(map[string]CachePolicy, len(DefaultCachePolicies)+len(config.CachePolicies))
+	for operation, policy := range DefaultCachePolicies {
+		policies[operation] = policy
+	}
+	for operation, policy := range config.CachePolicies {
+		policies[operation] = policy
+	}
+
+	// If MediaBaseURL is not provided, derive it from BaseURL by removing /graphql suffix
+	mediaRewriter := config.MediaRewriter
+	if mediaRewriter == nil {
+		mediaBaseURL := config.MediaBaseURL
+		if mediaBaseURL == "" {
+			mediaBaseURL = strings.TrimSuffix(config.BaseURL, "/graphql")
+		}
+		mediaRewriter = media.PrefixRewriter{BaseURL: mediaBaseURL}
 	}
 
-	return &CMSClient{
-		baseURL:      config.BaseURL,
-		mediaBaseURL: mediaBaseURL,
-		token:        config.Token,
+	mediaTransformer := media.NewTransformer(mediaRewriter)
+	if config.MediaPlaceholders {
+		mediaTransformer.WithPlaceholders(media.NewPlaceholders(media.PlaceholdersConfig{
+			BaseURL: strings.TrimSuffix(config.BaseURL, "/graphql"),
+			Cache:   config.Cache,
+		}))
+	}
+
+	client := &CMSClient{
+		baseURL: config.BaseURL,
+		media:   mediaTransformer,
+		pricing: config.PricingRules,
+		token:   config.Token,
 		httpClient: &http.Client{
 			Timeout: config.RequestTimeout,
 			Transport: &http.Transport{
@@ -66,18 +129,155 @@ func NewCMSClient(config Config) *CMSClient {
 		},
 		cache:      config.Cache,
 		defaultTTL: config.DefaultCacheTTL,
+		staleTTL:   config.StaleCacheTTL,
+		policies:   policies,
+		retry:      config.Retry.withDefaults(),
+		breaker:    newCircuitBreaker(config.Breaker),
 	}
+	client.persistedQueries.Store(!config.DisablePersistedQueries)
+	return client
+}
+
+// ClientStats holds request counters for the CMS client
+type ClientStats struct {
+	UpstreamRequests  uint64 `json:"upstreamRequests"`  // Requests actually sent to Strapi, including retries
+	CoalescedRequests uint64 `json:"coalescedRequests"` // Callers that shared another caller's in-flight request
+	StaleServed       uint64 `json:"staleServed"`       // Responses served from entries past their soft TTL
+	RefreshFailures   uint64 `json:"refreshFailures"`   // Background refreshes that failed and kept the stale entry
+	Retries           uint64 `json:"retries"`           // Requests retried after a transient failure
+	BreakerRejections uint64 `json:"breakerRejections"` // Requests failed fast while the circuit was open
+}
+
+// Stats returns a snapshot of the client's request counters
+func (c *CMSClient) Stats() ClientStats {
+	return ClientStats{
+		UpstreamRequests:  c.upstreamRequests.Load(),
+		CoalescedRequests: c.coalescedRequests.Load(),
+		StaleServed:       c.staleServed.Load(),
+		RefreshFailures:   c.refreshFailures.Load(),
+		Retries:           c.retries.Load(),
+		BreakerRejections: c.breakerRejections.Load(),
+	}
+}
+
+// BreakerStatus returns the state of the circuit breaker in front of the CMS
+func (c *CMSClient) BreakerStatus() BreakerStatus {
+	return c.breaker.Status()
+}
+
+// CachePolicyFor returns the cache policy for the given query, resolved against the client defaults
+func (c *CMSClient) CachePolicyFor(query string) CachePolicy {
+	policy, ok := c.policies[OperationName(query)]
+	if !ok {
+		return CachePolicy{TTL: c.defaultTTL, StaleTTL: c.staleTTL}
+	}
+	if policy.TTL == 0 {
+		policy.TTL = c.defaultTTL
+	}
+	return policy
 }
 
 // InvalidateCache invalidates cache entries matching the given pattern
 func (c *CMSClient) InvalidateCache(ctx context.Context, pattern string) error {
-	return c.cache.DeletePattern(ctx, pattern)
+	if err := c.cache.DeletePattern(ctx, pattern); err != nil {
+		return newCacheError("cache invalidation", err)
+	}
+	return nil
+}
+
+// InvalidateTags invalidates every cached response tagged with any of the given tags
+// (see ContentTypeTag and DocumentTag) and returns the number of entries removed.
+// If the cache cannot index tags, all CMS GraphQL entries are invalidated instead.
+// Cache failures are returned as ErrCache errors.
+func (c *CMSClient) InvalidateTags(ctx context.Context, tags ...string) (int, error) {
+	taggedCache, ok := c.cache.(cache.TaggedCache)
+	if ok {
+		removed, err := taggedCache.InvalidateTags(ctx, tags...)
+		if err == nil {
+			return removed, nil
+		}
+		if !errors.Is(err, cache.ErrTagsUnsupported) {
+			return 0, newCacheError("tag invalidation", err)
+		}
+	}
+
+	fmt.Printf("[GraphQL Client] Cache does not support tags, invalidating all CMS entries\n")
+	return 0, c.InvalidateCache(ctx, "cms:graphql:*")
+}
+
+// rewarmTimeout bounds a background re-warm after an invalidation
+const rewarmTimeout = 2 * time.Minute
+
+// InvalidateTagsAndRewarm invalidates like InvalidateTags, then re-executes the requests of the
+// removed entries in the background so the next client request is a cache hit again
+func (c *CMSClient) InvalidateTagsAndRewarm(ctx context.Context, tags ...string) (int, error) {
+	taggedCache, ok := c.cache.(cache.TaggedCache)
+	if !ok {
+		return c.InvalidateTags(ctx, tags...)
+	}
+
+	// Capture the requests behind the tagged entries before they are removed
+	keys, err := taggedCache.TaggedKeys(ctx, tags...)
+	if errors.Is(err, cache.ErrTagsUnsupported) {
+		return c.InvalidateTags(ctx, tags...)
+	}
+	if err != nil {
+		return 0, newCacheError("tag lookup", err)
+	}
+
+	rewarm := make(map[string]cacheEntry, len(keys))
+	for _, key := range keys {
+		cached, err := c.cache.Get(ctx, key)
+		if err != nil || cached == nil {
+			continue
+		}
+		if entry := decodeCacheEntry(cached); entry.Query != "" {
+			rewarm[key] = entry
+		}
+	}
+
+	removed, err := taggedCache.InvalidateTags(ctx, tags...)
+	if err != nil {
+		return 0, newCacheError("tag invalidation", err)
+	}
+
+	// The caller's context may be recycled once its request ends (fasthttp pools them), so the
+	// re-warm runs on a fresh context that only carries the locale and has its own deadline
+	rewarmCtx, cancel := context.WithTimeout(locale.WithLocale(context.Background(), locale.FromContext(ctx)), rewarmTimeout)
+	go func() {
+		defer cancel()
+		for cacheKey, entry := range rewarm {
+			policy := c.CachePolicyFor(entry.Query)
+			_, err, _ := c.inflight.Do(cacheKey, func() (interface{}, error) {
+				return c.fetchGraphQL(rewarmCtx, entry.Query, entry.Variables, cacheKey, policy)
+			})
+			if err != nil {
+				fmt.Printf("[GraphQL Client] Re-warm failed for key %s: %v\n", cacheKey, err)
+			}
+		}
+		fmt.Printf("[GraphQL Client] Re-warmed %d entries for tags %v\n", len(rewarm), tags)
+	}()
+
+	return removed, nil
 }
 
 // GraphQLRequest represents a GraphQL query request
 type GraphQLRequest struct {
-	Query     string                 `json:"query"`
-	Variables map[string]interface{} `json:"variables,omitempty"`
+	Query         string                    `json:"query,omitempty"` // Omitted when sending a persisted query by hash
+	OperationName string                    `json:"operationName,omitempty"`
+	Variables     map[string]interface{}    `json:"variables,omitempty"`
+	Extensions    *GraphQLRequestExtensions `json:"extensions,omitempty"`
+}
+
+// GraphQLRequestExtensions holds protocol extensions of a GraphQL request
+type GraphQLRequestExtensions struct {
+	PersistedQuery *PersistedQuery `json:"persistedQuery,omitempty"`
+}
+
+// PersistedQuery identifies a query by its SHA-256 hash (Apollo automatic persisted queries)
+type PersistedQuery struct {
+	Version    int    `json:"version"`
+	SHA256Hash string `json:"sha256Hash"`
 }
 
 // GraphQLResponse represents a GraphQL response
@@ -88,12 +288,31 @@ type GraphQLResponse struct {
 
 // GraphQLError represents a GraphQL error
 type GraphQLError struct {
-	Message string        `json:"message"`
-	Path    []interface{} `json:"path,omitempty"`
+	Message    string                 `json:"message"`
+	Path       []interface{}          `json:"path,omitempty"`
+	Extensions GraphQLErrorExtensions `json:"extensions,omitempty"`
 }
 
-// ExecuteGraphQL executes a GraphQL query with caching support
+// GraphQLErrorExtensions holds the machine-readable part of a GraphQL error
+type GraphQLErrorExtensions struct {
+	Code string `json:"code,omitempty"`
+}
+
+// ExecuteGraphQL executes a GraphQL query with caching support, using the cache policy
+// registered for the query's operation name.
 func (c *CMSClient) ExecuteGraphQL(ctx context.Context, query string, variables map[string]interface{}) (json.RawMessage, error) {
+	return c.ExecuteGraphQLWithPolicy(ctx, query, variables, c.CachePolicyFor(query))
+}
+
+// ExecuteGraphQLWithPolicy executes a GraphQL query with an explicit cache policy.
+// Concurrent callers that miss the cache with the same cache key share a single upstream request.
+// Entries past their soft TTL are returned immediately and refreshed in the background; if the
+// refresh fails the last good payload keeps being served until the policy's stale window ends.
+func (c *CMSClient) ExecuteGraphQLWithPolicy(ctx context.Context, query string, variables map[string]interface{}, policy CachePolicy) (json.RawMessage, error) {
+	if policy.TTL == 0 {
+		policy.TTL = c.defaultTTL
+	}
+
 	// Get locale from context
 	reqLocale := locale.FromContext(ctx)
 
@@ -109,15 +328,175 @@ func (c *CMSClient) ExecuteGraphQL(ctx context.Context, query string, variables
 	cacheKey := c.buildCacheKey(query, variables, reqLocale)
 
 	// Check cache first
-	if cached, err := c.cache.Get(ctx, cacheKey); err == nil && cached != nil {
-		fmt.Printf("[GraphQL Client] Cache hit for key: %s\n", cacheKey)
-		return cached, nil
+	var cached []byte
+	if !policy.Disabled {
+		cached, _ = c.cache.Get(ctx, cacheKey)
 	}
-	// Create request body
-	reqBody := GraphQLRequest{
-		Query:     query,
-		Variables: variables,
+	if cached != nil {
+		entry := decodeCacheEntry(cached)
+		if !entry.IsStale(policy.TTL) {
+			fmt.Printf("[GraphQL Client] Cache hit for key: %s\n", cacheKey)
+			c.media.Prefetch(ctx, entry.Media)
+			return entry.Data, nil
+		}
+
+		// The entry may have been written under a longer policy for the same key (e.g. a search
+		// before the autocomplete override); past this policy's stale window it is a miss
+		if !entry.IsStale(policy.TTL + policy.StaleTTL) {
+			fmt.Printf("[GraphQL Client] Stale cache hit for key: %s\n", cacheKey)
+			c.staleServed.Add(1)
+			markStale(ctx)
+			c.refreshInBackground(ctx, query, variables, cacheKey, policy)
+			c.media.Prefetch(ctx, entry.Media)
+			return entry.Data, nil
+		}
+	}
+
+	// Coalesce concurrent misses. The shared request must not be cancelled when the
+	// caller that started it goes away, so it runs detached from the caller's
+	// cancellation and is bounded by the HTTP client timeout instead.
+	leader := false
+	resultChan := c.inflight.DoChan(cacheKey, func() (interface{}, error) {
+		leader = true
+		return c.fetchGraphQL(context.WithoutCancel(ctx), query, variables, cacheKey, policy)
+	})
+
+	select {
+	case res := <-resultChan:
+		if !leader {
+			c.coalescedRequests.Add(1)
+			fmt.Printf("[GraphQL Client] Coalesced request for key: %s\n", cacheKey)
+		}
+		if res.Err != nil {
+			return nil, res.Err
+		}
+		entry := res.Val.(cacheEntry)
+		c.media.Prefetch(ctx, entry.Media)
+		return entry.Data, nil
+	case <-ctx.Done():
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			return nil, &Error{Kind: ErrTimeout, Err: ctx.Err()}
+		}
+		return nil, ctx.Err()
 	}
+}
+
+// refreshInBackground re-fetches a stale entry without blocking the caller.
+// At most one refresh per key runs at a time; a failed refresh leaves the stale entry in place.
+func (c *CMSClient) refreshInBackground(ctx context.Context, query string, variables map[string]interface{}, cacheKey string, policy CachePolicy) {
+	if _, running := c.refreshing.LoadOrStore(cacheKey, struct{}{}); running {
+		return
+	}
+
+	refreshCtx := context.WithoutCancel(ctx)
+	go func() {
+		defer c.refreshing.Delete(cacheKey)
+
+		_, err, _ := c.inflight.Do(cacheKey, func() (interface{}, error) {
+			return c.fetchGraphQL(refreshCtx, query, variables, cacheKey, policy)
+		})
+		if err != nil {
+			c.refreshFailures.Add(1)
+			fmt.Printf("[GraphQL Client] Background refresh failed for key %s, serving stale: %v\n", cacheKey, err)
+		}
+	}()
+}
+
+// fetchGraphQL sends the query to Strapi, retrying transient failures, and caches a
+// successful response according to policy, returning the entry built for it. While the
+// circuit breaker is open it fails fast so that callers are not held up by an unhealthy CMS.
+func (c *CMSClient) fetchGraphQL(ctx context.Context, query string, variables map[string]interface{}, cacheKey string, policy CachePolicy) (cacheEntry, error) {
+	var data json.RawMessage
+	for attempt := 0; ; attempt++ {
+		if !c.breaker.allow() {
+			c.breakerRejections.Add(1)
+			return cacheEntry{}, &Error{Kind: ErrUnavailable, Message: "CMS circuit breaker open"}
+		}
+
+		var err error
+		data, err = c.doGraphQL(ctx, query, variables)
+		c.breaker.record(err)
+		if err == nil {
+			break
+		}
+		if attempt >= c.retry.MaxRetries || !isRetryable(err) {
+			return cacheEntry{}, err
+		}
+
+		delay := c.retry.backoff(attempt)
+		c.retries.Add(1)
+		fmt.Printf("[GraphQL Client] Retrying in %s after: %v\n", delay, err)
+		select {
+		case <-time.After(delay):
+		case <-ctx.Done():
+			return cacheEntry{}, err
+		}
+	}
+
+	// Cache successful response; it stays in the cache for the soft TTL plus the stale window
+	entry := newCacheEntry(data, query, variables, c.media.Files(data))
+	if data != nil && !policy.Disabled {
+		if entryBytes, err := json.Marshal(entry); err == nil {
+			ttl := policy.TTL + policy.StaleTTL
+			if taggedCache, ok := c.cache.(cache.TaggedCache); ok {
+				tags := extractCacheTags(data)
+				_ = taggedCache.SetWithTags(ctx, cacheKey, entryBytes, ttl, tags)
+				fmt.Printf("[GraphQL Client] Cached response with key: %s, tags: %v\n", cacheKey, tags)
+			} else {
+				_ = c.cache.Set(ctx, cacheKey, entryBytes, ttl)
+				fmt.Printf("[GraphQL Client] Cached response with key: %s\n", cacheKey)
+			}
+		}
+	}
+
+	return entry, nil
+}
+
+// doGraphQL sends a query to Strapi. Registered operations are sent by operation name and
+// hash only; the full query is sent when Strapi does not know the hash yet, which registers
+// it, when it rejects the hash-only request, or when Strapi does not support persisted queries.
+func (c *CMSClient) doGraphQL(ctx context.Context, query string, variables map[string]interface{}) (json.RawMessage, error) {
+	op := lookupOperation(query)
+	if op == nil || !c.persistedQueries.Load() {
+		return c.postGraphQL(ctx, GraphQLRequest{Query: query, Variables: variables})
+	}
+
+	extensions := &GraphQLRequestExtensions{
+		PersistedQuery: &PersistedQuery{Version: 1, SHA256Hash: op.Hash},
+	}
+	data, err := c.postGraphQL(ctx, GraphQLRequest{
+		OperationName: op.Name,
+		Variables:     variables,
+		Extensions:    extensions,
+	})
+
+	switch graphQLErrorCode(err) {
+	case codePersistedQueryNotFound:
+		// First use since Strapi started; sending the query with its hash registers it
+	case codePersistedQueryNotSupported:
+		fmt.Printf("[GraphQL Client] Persisted queries not supported by the CMS, sending full queries\n")
+		c.persistedQueries.Store(false)
+		extensions = nil
+	case codeBadRequest:
+		// May be a one-off rejection of the hash-only request; retry this request with the
+		// full query but keep persisted queries on
+		fmt.Printf("[GraphQL Client] Persisted query %s rejected, resending the full query\n", op.Name)
+		extensions = nil
+	default:
+		return data, err
+	}
+
+	return c.postGraphQL(ctx, GraphQLRequest{
+		Query:         query,
+		OperationName: op.Name,
+		Variables:     variables,
+		Extensions:    extensions,
+	})
+}
+
+// postGraphQL sends a single GraphQL request to Strapi and classifies its failures
+func (c *CMSClient) postGraphQL(ctx context.Context, reqBody GraphQLRequest) (json.RawMessage, error) {
+	c.upstreamRequests.Add(1)
 
 	bodyBytes, err := json.Marshal(reqBody)
 	if err != nil {
@@ -138,44 +517,61 @@ func (c *CMSClient) ExecuteGraphQL(ctx context.Context, query string, variables
 
 	// Debug logging
 	fmt.Printf("[GraphQL Client] POST %s\n", c.baseURL)
-	fmt.Printf("[GraphQL Client] Query: %s\n", query)
-	if variables != nil {
-		varsJSON, _ := json.Marshal(variables)
+	if reqBody.Query != "" {
+		fmt.Printf("[GraphQL Client] Query: %s\n", reqBody.Query)
+	} else {
+		fmt.Printf("[GraphQL Client] Persisted query: %s (%s)\n", reqBody.OperationName, reqBody.Extensions.PersistedQuery.SHA256Hash)
+	}
+	if reqBody.Variables != nil {
+		varsJSON, _ := json.Marshal(reqBody.Variables)
 		fmt.Printf("[GraphQL Client] Variables: %s\n", string(varsJSON))
 	}
 
 	// Execute request
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
-		return nil, fmt.Errorf("GraphQL request failed: %w", err)
+		var netErr net.Error
+		if errors.Is(err, context.DeadlineExceeded) || (errors.As(err, &netErr) && netErr.Timeout()) {
+			return nil, &Error{Kind: ErrTimeout, Err: err}
+		}
+		return nil, &Error{Kind: ErrUnavailable, Message: "GraphQL request failed", Err: err}
 	}
 	defer resp.Body.Close()
 
 	// Read response body
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return nil, fmt.Errorf("failed to read GraphQL response: %w", err)
+		return nil, &Error{Kind: ErrUnavailable, Message: "failed to read GraphQL response", Err: err}
 	}
 
 	// Debug logging
 	fmt.Printf("[GraphQL Client] Response Status: %d\n", resp.StatusCode)
 	fmt.Printf("[GraphQL Client] Response Body: %s\n", string(body))
 
+	// Strapi down or restarting behind a proxy; the body is usually not GraphQL
+	switch resp.StatusCode {
+	case http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return nil, &Error{Kind: ErrUnavailable, Message: fmt.Sprintf("CMS responded with status %d", resp.StatusCode)}
+	}
+	if resp.StatusCode >= http.StatusInternalServerError && !json.Valid(body) {
+		return nil, &Error{Kind: ErrUpstream, Message: fmt.Sprintf("CMS responded with status %d", resp.StatusCode)}
+	}
+
 	// Parse GraphQL response
 	var graphqlResp GraphQLResponse
 	if err := json.Unmarshal(body, &graphqlResp); err != nil {
-		return nil, fmt.Errorf("failed to unmarshal GraphQL response: %w", err)
+		if resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden {
+			return nil, &Error{Kind: ErrForbidden, Message: fmt.Sprintf("CMS responded with status %d", resp.StatusCode)}
+		}
+		return nil, newDecodeError("GraphQL response", err)
 	}
 
 	// Check for GraphQL errors
 	if len(graphqlResp.Errors) > 0 {
-		return nil, fmt.Errorf("GraphQL errors: %v", graphqlResp.Errors)
+		return nil, newGraphQLError(graphqlResp.Errors)
 	}
-
-	// Cache successful response
-	if graphqlResp.Data != nil {
-		_ = c.cache.Set(ctx, cacheKey, graphqlResp.Data, c.defaultTTL)
-		fmt.Printf("[GraphQL Client] Cached response with key: %s\n", cacheKey)
+	if resp.StatusCode >= http.StatusBadRequest {
+		return nil, &Error{Kind: ErrUpstream, Message: fmt.Sprintf("CMS responded with status %d", resp.StatusCode)}
 	}
 
 	return graphqlResp.Data, nil
@@ -200,26 +596,17 @@ func (c *CMSClient) buildCacheKey(query string, variables map[string]interface{}
 	return fmt.Sprintf("cms:graphql:%x", hash)
 }
 
-// PrefixMediaURL adds the media base URL prefix to a relative URL
-// Returns empty string if url is empty, returns as-is if already absolute
+// PrefixMediaURL rewrites a media URL returned by Strapi with the client's URL rewriter
+// Returns empty string if url is empty
 func (c *CMSClient) PrefixMediaURL(url string) string {
-	if url == "" {
-		return ""
-	}
-	// If URL is already absolute, return as-is
-	if strings.HasPrefix(url, "http://") || strings.HasPrefix(url, "https://") {
-		return url
-	}
-	// Add prefix to relative URL
-	return c.mediaBaseURL + url
+	return c.media.RewriteURL(url)
+}
+
+// Media returns the transformer the services use to convert Strapi media fields
+func (c *CMSClient) Media() *media.Transformer {
+	return c.media
 }
 
 // strapiMediaField represents the raw media field structure from Strapi GraphQL
 // This is used internally by services to parse media fields from GraphQL responses
-type strapiMediaField struct {
-	DocumentID string                 `json:"documentId"`
-	URL        string                 `json:"url"`
-	Width      int                    `json:"width"`
-	Height     int                    `json:"height"`
-	Formats    map[string]interface{} `json:"formats"`
-}
+type strapiMediaField = media.File
@@ -0,0 +1,249 @@
+package cms
+
+import (
+	"api-gateway/pkg/cache"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// newStubCMS starts a GraphQL endpoint that counts the requests it receives
+func newStubCMS(t *testing.T, handler http.HandlerFunc) (*httptest.Server, *atomic.Int64) {
+	t.Helper()
+	var requests atomic.Int64
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		handler(w, r)
+	}))
+	t.Cleanup(server.Close)
+	return server, &requests
+}
+
+// waitFor polls condition until it holds or a second has passed
+func waitFor(t *testing.T, condition func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(time.Second)
+	for !condition() {
+		if time.Now().After(deadline) {
+			t.Fatal("condition not met within 1s")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestExecuteGraphQLCoalescesConcurrentMisses(t *testing.T) {
+	const callers = 10
+
+	release := make(chan struct{})
+	server, requests := newStubCMS(t, func(w http.ResponseWriter, r *http.Request) {
+		<-release
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"data":{"brands":[{"documentId":"bmw"}]}}`))
+	})
+	client := NewCMSClient(Config{BaseURL: server.URL, DisablePersistedQueries: true})
+
+	const query = `query { brands { documentId } }`
+	var wg sync.WaitGroup
+	results := make([]string, callers)
+	errs := make([]error, callers)
+	for i := range callers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			data, err := client.ExecuteGraphQL(context.Background(), query, map[string]interface{}{"limit": 5})
+			results[i], errs[i] = string(data), err
+		}()
+	}
+
+	// Hold the leader's request until every other caller has had time to join it
+	waitFor(t, func() bool { return requests.Load() == 1 })
+	time.Sleep(100 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	for i := range callers {
+		if errs[i] != nil || results[i] != `{"brands":[{"documentId":"bmw"}]}` {
+			t.Errorf("caller %d got %s, %v", i, results[i], errs[i])
+		}
+	}
+	if got := requests.Load(); got != 1 {
+		t.Errorf("upstream requests = %d, want 1", got)
+	}
+	stats := client.Stats()
+	if stats.UpstreamRequests != 1 || stats.CoalescedRequests != callers-1 {
+		t.Errorf("stats = %+v, want 1 upstream and %d coalesced requests", stats, callers-1)
+	}
+}
+
+// seedCacheEntry stores data for a request as if it had been fetched age ago
+func seedCacheEntry(t *testing.T, client *CMSClient, query string, variables map[string]interface{}, data string, age time.Duration) string {
+	t.Helper()
+	entry := newCacheEntry(json.RawMessage(data), query, variables, nil)
+	entry.StoredAt = time.Now().Add(-age)
+	raw, err := json.Marshal(entry)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cacheKey := client.buildCacheKey(query, variables, "en")
+	client.cache.Set(context.Background(), cacheKey, raw, time.Hour)
+	return cacheKey
+}
+
+func TestExecuteGraphQLServesStaleAndRefreshesOnce(t *testing.T) {
+	release := make(chan struct{})
+	server, requests := newStubCMS(t, func(w http.ResponseWriter, r *http.Request) {
+		<-release
+		w.Write([]byte(`{"data":{"version":2}}`))
+	})
+	client := NewCMSClient(Config{
+		BaseURL:                 server.URL,
+		Cache:                   cache.NewMemoryCache(cache.MemoryCacheConfig{}),
+		DefaultCacheTTL:         time.Minute,
+		StaleCacheTTL:           time.Hour,
+		DisablePersistedQueries: true,
+	})
+
+	const query = `query { version }`
+	cacheKey := seedCacheEntry(t, client, query, map[string]interface{}{"locale": "en"}, `{"version":1}`, 2*time.Minute)
+
+	for range 5 {
+		ctx, tracker := WithStaleTracker(context.Background())
+		data, err := client.ExecuteGraphQL(ctx, query, nil)
+		if err != nil || string(data) != `{"version":1}` || !tracker.Stale() {
+			t.Fatalf("got %s, %v, stale %v; want the stale entry", data, err, tracker.Stale())
+		}
+	}
+
+	waitFor(t, func() bool { return requests.Load() == 1 })
+	close(release)
+	waitFor(t, func() bool {
+		cached, _ := client.cache.Get(context.Background(), cacheKey)
+		return string(decodeCacheEntry(cached).Data) == `{"version":2}`
+	})
+
+	ctx, tracker := WithStaleTracker(context.Background())
+	data, err := client.ExecuteGraphQL(ctx, query, nil)
+	if err != nil || string(data) != `{"version":2}` || tracker.Stale() {
+		t.Errorf("got %s, %v, stale %v; want the refreshed entry", data, err, tracker.Stale())
+	}
+	if got := requests.Load(); got != 1 {
+		t.Errorf("upstream requests = %d, want 1 refresh", got)
+	}
+	if stats := client.Stats(); stats.StaleServed != 5 {
+		t.Errorf("stale served = %d, want 5", stats.StaleServed)
+	}
+}
+
+func TestExecuteGraphQLKeepsServingStaleWhenRefreshFails(t *testing.T) {
+	server, requests := newStubCMS(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	})
+	client := NewCMSClient(Config{
+		BaseURL:                 server.URL,
+		Cache:                   cache.NewMemoryCache(cache.MemoryCacheConfig{}),
+		DefaultCacheTTL:         time.Minute,
+		StaleCacheTTL:           time.Hour,
+		Retry:                   RetryConfig{MaxRetries: -1},
+		DisablePersistedQueries: true,
+	})
+
+	const query = `query { version }`
+	cacheKey := seedCacheEntry(t, client, query, map[string]interface{}{"locale": "en"}, `{"version":1}`, 2*time.Minute)
+	refreshed := func() bool {
+		_, running := client.refreshing.Load(cacheKey)
+		return !running
+	}
+
+	data, err := client.ExecuteGraphQL(context.Background(), query, nil)
+	if err != nil || string(data) != `{"version":1}` {
+		t.Fatalf("got %s, %v; want the stale entry", data, err)
+	}
+	waitFor(t, refreshed)
+
+	data, err = client.ExecuteGraphQL(context.Background(), query, nil)
+	if err != nil || string(data) != `{"version":1}` {
+		t.Errorf("after a failed refresh got %s, %v; want the stale entry", data, err)
+	}
+	waitFor(t, refreshed)
+	if failures := client.Stats().RefreshFailures; failures != 2 {
+		t.Errorf("refresh failures = %d, want 2", failures)
+	}
+	if got := requests.Load(); got != 2 {
+		t.Errorf("upstream requests = %d, want one per stale read", got)
+	}
+}
+
+func TestExecuteGraphQLWithPolicyChecksStaleWindowOnRead(t *testing.T) {
+	server, requests := newStubCMS(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"data":{"version":2}}`))
+	})
+	client := NewCMSClient(Config{
+		BaseURL:                 server.URL,
+		Cache:                   cache.NewMemoryCache(cache.MemoryCacheConfig{}),
+		DefaultCacheTTL:         10 * time.Minute,
+		StaleCacheTTL:           time.Hour,
+		DisablePersistedQueries: true,
+	})
+	short := CachePolicy{TTL: 2 * time.Minute}
+
+	tests := []struct {
+		name        string
+		age         time.Duration
+		policy      CachePolicy
+		want        string
+		wantFetched bool
+	}{
+		{"fresh under the short policy", time.Minute, short, `{"version":1}`, false},
+		{"past the short policy without a stale window", 3 * time.Minute, short, `{"version":2}`, true},
+		{"past the short policy's stale window", 10 * time.Minute, CachePolicy{TTL: 2 * time.Minute, StaleTTL: 5 * time.Minute}, `{"version":2}`, true},
+		{"fresh under the default policy", 3 * time.Minute, client.CachePolicyFor(`query { version }`), `{"version":1}`, false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			const query = `query { version }`
+			seedCacheEntry(t, client, query, map[string]interface{}{"locale": "en"}, `{"version":1}`, test.age)
+			before := requests.Load()
+
+			ctx, tracker := WithStaleTracker(context.Background())
+			data, err := client.ExecuteGraphQLWithPolicy(ctx, query, nil, test.policy)
+			if err != nil || string(data) != test.want {
+				t.Fatalf("got %s, %v; want %s", data, err, test.want)
+			}
+			if tracker.Stale() {
+				t.Error("response marked stale")
+			}
+			if fetched := requests.Load() > before; fetched != test.wantFetched {
+				t.Errorf("fetched = %v, want %v", fetched, test.wantFetched)
+			}
+		})
+	}
+}
+
+func TestExecuteGraphQLKeepsMediaWithCacheEntry(t *testing.T) {
+	server, _ := newStubCMS(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"data":{"brand":{"documentId":"bmw","Logo":{"documentId":"logo","mime":"image/png","updatedAt":"2025-01-01T00:00:00.000Z","url":"/uploads/logo.png"}}}}`))
+	})
+	memoryCache := cache.NewMemoryCache(cache.MemoryCacheConfig{})
+	client := NewCMSClient(Config{BaseURL: server.URL, Cache: memoryCache, DisablePersistedQueries: true, MediaPlaceholders: true})
+
+	const query = `query { brand { documentId Logo { documentId mime updatedAt url } } }`
+	if _, err := client.ExecuteGraphQL(context.Background(), query, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	cached, _ := memoryCache.Get(context.Background(), client.buildCacheKey(query, map[string]interface{}{"locale": "en"}, "en"))
+	if cached == nil {
+		t.Fatal("response not cached")
+	}
+	entry := decodeCacheEntry(cached)
+	if len(entry.Media) != 1 || entry.Media[0].DocumentID != "logo" || entry.Media[0].Version != "2025-01-01T00:00:00.000Z" {
+		t.Errorf("entry.Media = %+v, want the logo with its updatedAt", entry.Media)
+	}
+}
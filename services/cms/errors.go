@@ -0,0 +1,122 @@
+package cms
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Error kinds returned by the CMS client and services; match them with errors.Is
+var (
+	ErrNotFound    = errors.New("not found")
+	ErrBadInput    = errors.New("invalid input")
+	ErrForbidden   = errors.New("access to CMS content forbidden")
+	ErrTimeout     = errors.New("CMS request timed out")
+	ErrUnavailable = errors.New("CMS unavailable")
+	ErrValidation  = errors.New("GraphQL query failed validation")
+	ErrDecode      = errors.New("failed to decode CMS response")
+	ErrUpstream    = errors.New("CMS returned an error")
+	ErrCache       = errors.New("cache operation failed")
+)
+
+// GraphQL error codes sent by Strapi (Apollo Server) in extensions.code
+const (
+	codeValidationFailed = "GRAPHQL_VALIDATION_FAILED"
+	codeParseFailed      = "GRAPHQL_PARSE_FAILED"
+	codeBadUserInput     = "BAD_USER_INPUT"
+	codeForbidden        = "FORBIDDEN"
+	codeUnauthenticated  = "UNAUTHENTICATED"
+	codeNotFound         = "NOT_FOUND"
+
+	codePersistedQueryNotFound     = "PERSISTED_QUERY_NOT_FOUND"
+	codePersistedQueryNotSupported = "PERSISTED_QUERY_NOT_SUPPORTED"
+	codeBadRequest                 = "BAD_REQUEST"
+)
+
+// Error is a classified CMS error. Kind is one of the Err* values above;
+// GraphQLErrors holds the errors reported by Strapi, if any.
+type Error struct {
+	Kind          error
+	Message       string // Internal description, may contain upstream text; never returned to clients
+	Detail        string // Written by the gateway itself and safe to return to clients, e.g. "brand not found"
+	GraphQLErrors []GraphQLError
+	Err           error // Underlying cause, if any
+}
+
+// Error returns the message (or detail) followed by the underlying cause
+func (e *Error) Error() string {
+	msg := e.Message
+	if msg == "" {
+		msg = e.Detail
+	}
+	if msg == "" {
+		msg = e.Kind.Error()
+	}
+	if e.Err != nil {
+		return msg + ": " + e.Err.Error()
+	}
+	return msg
+}
+
+// Unwrap exposes both the kind and the cause to errors.Is and errors.As
+func (e *Error) Unwrap() []error {
+	if e.Err != nil {
+		return []error{e.Kind, e.Err}
+	}
+	return []error{e.Kind}
+}
+
+// newNotFoundError reports a missing entity, e.g. newNotFoundError("brand")
+func newNotFoundError(entity string) *Error {
+	return &Error{Kind: ErrNotFound, Detail: entity + " not found"}
+}
+
+// newBadInputError reports invalid parameters of a service call
+func newBadInputError(message string) *Error {
+	return &Error{Kind: ErrBadInput, Detail: message}
+}
+
+// newDecodeError reports a response that could not be unmarshalled into the expected shape
+func newDecodeError(what string, err error) *Error {
+	return &Error{Kind: ErrDecode, Message: "failed to unmarshal " + what, Err: err}
+}
+
+// newCacheError reports a failed cache operation such as an invalidation
+func newCacheError(operation string, err error) *Error {
+	return &Error{Kind: ErrCache, Message: operation + " failed", Err: err}
+}
+
+// newGraphQLError classifies the errors of a GraphQL response by the code of the first error
+func newGraphQLError(graphqlErrors []GraphQLError) *Error {
+	kind := ErrUpstream
+	switch graphqlErrors[0].Extensions.Code {
+	case codeValidationFailed, codeParseFailed:
+		kind = ErrValidation
+	case codeBadUserInput:
+		kind = ErrBadInput
+	case codeForbidden, codeUnauthenticated:
+		kind = ErrForbidden
+	case codeNotFound:
+		kind = ErrNotFound
+	}
+
+	messages := make([]string, len(graphqlErrors))
+	for i, graphqlErr := range graphqlErrors {
+		messages[i] = graphqlErr.Message
+	}
+
+	return &Error{
+		Kind:          kind,
+		Message:       fmt.Sprintf("GraphQL errors: %s", strings.Join(messages, "; ")),
+		GraphQLErrors: graphqlErrors,
+	}
+}
+
+// graphQLErrorCode returns the extensions.code of the first GraphQL error in err, if any
+func graphQLErrorCode(err error) string {
+	var cmsErr *Error
+	if !errors.As(err, &cmsErr) || len(cmsErr.GraphQLErrors) == 0 {
+		return ""
+	}
+	return cmsErr.GraphQLErrors[0].Extensions.Code
+}
@@ -0,0 +1,64 @@
+package cms
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestNewGraphQLError(t *testing.T) {
+	tests := []struct {
+		code string
+		want error
+	}{
+		{codeValidationFailed, ErrValidation},
+		{codeParseFailed, ErrValidation},
+		{codeBadUserInput, ErrBadInput},
+		{codeForbidden, ErrForbidden},
+		{codeUnauthenticated, ErrForbidden},
+		{codeNotFound, ErrNotFound},
+		{"INTERNAL_SERVER_ERROR", ErrUpstream},
+		{"", ErrUpstream},
+	}
+
+	for _, test := range tests {
+		err := newGraphQLError([]GraphQLError{
+			{Message: "first", Extensions: GraphQLErrorExtensions{Code: test.code}},
+			{Message: "second", Extensions: GraphQLErrorExtensions{Code: codeForbidden}},
+		})
+		if !errors.Is(err, test.want) {
+			t.Errorf("code %q classified as %v, want %v", test.code, err.Kind, test.want)
+		}
+		if err.Error() != "GraphQL errors: first; second" {
+			t.Errorf("Error() = %q", err.Error())
+		}
+		if graphQLErrorCode(err) != test.code {
+			t.Errorf("graphQLErrorCode() = %q, want %q", graphQLErrorCode(err), test.code)
+		}
+	}
+}
+
+func TestErrorMessageAndUnwrap(t *testing.T) {
+	cause := errors.New("connection refused")
+	tests := []struct {
+		name string
+		err  *Error
+		want string
+	}{
+		{"message", &Error{Kind: ErrUnavailable, Message: "GraphQL request failed", Err: cause}, "GraphQL request failed: connection refused"},
+		{"detail", newNotFoundError("brand"), "brand not found"},
+		{"kind", &Error{Kind: ErrTimeout}, "CMS request timed out"},
+		{"kind and cause", &Error{Kind: ErrTimeout, Err: cause}, "CMS request timed out: connection refused"},
+	}
+
+	for _, test := range tests {
+		if got := test.err.Error(); got != test.want {
+			t.Errorf("%s: Error() = %q, want %q", test.name, got, test.want)
+		}
+		if !errors.Is(test.err, test.err.Kind) {
+			t.Errorf("%s: errors.Is(err, %v) = false", test.name, test.err.Kind)
+		}
+		if test.err.Err != nil && !errors.Is(test.err, cause) {
+			t.Errorf("%s: cause not unwrapped", test.name)
+		}
+	}
+}
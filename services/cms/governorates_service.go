@@ -26,19 +26,9 @@ func (s *GovernorateServiceGraphQL) GetAll(ctx context.Context) ([]models.Govern
 		return nil, fmt.Errorf("failed to fetch governorates: %w", err)
 	}
 
-	var result struct {
-		Governorates []struct {
-			DocumentID string `json:"documentId"`
-			Name       string `json:"Name"`
-			Cities     []struct {
-				DocumentID string `json:"documentId"`
-				Name       string `json:"Name"`
-			} `json:"cities"`
-		} `json:"governorates"`
-	}
-
+	var result getGovernatesResult
 	if err := json.Unmarshal(data, &result); err != nil {
-		return nil, fmt.Errorf("failed to unmarshal governorates: %w", err)
+		return nil, newDecodeError("governorates", err)
 	}
 
 	governorates := make([]models.GovernorateWithCities, len(result.Governorates))
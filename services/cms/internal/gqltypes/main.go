@@ -0,0 +1,335 @@
+// Command gqltypes generates typed Go response structs for the CMS GraphQL operations.
+//
+// It reads every string constant holding a named operation from a Go source file
+// (queries.go), validates the operations against the Strapi schema (schema.gql) and
+// writes one result struct per operation, with a named struct for every nested
+// selection. Run it through go generate in services/cms:
+//
+//	go generate ./services/cms
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"log"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/vektah/gqlparser/v2"
+	gqlast "github.com/vektah/gqlparser/v2/ast"
+)
+
+// scalarTypes maps GraphQL scalars to Go types. Nullable numbers (see nullableScalars) are
+// generated as pointers; other nullable scalars use the same types and a null value decodes
+// to the zero value.
+var scalarTypes = map[string]string{
+	"ID":             "string",
+	"String":         "string",
+	"Int":            "int",
+	"Long":           "int64",
+	"Float":          "float64",
+	"Boolean":        "bool",
+	"DateTime":       "string",
+	"Date":           "string",
+	"Time":           "string",
+	"I18NLocaleCode": "string",
+	"JSON":           "interface{}",
+}
+
+// nullableScalars are the scalars whose nullable fields are generated as pointers, so that
+// a missing price or spec is not read as 0
+var nullableScalars = map[string]bool{
+	"Int":   true,
+	"Long":  true,
+	"Float": true,
+}
+
+// fieldNames renames schema fields whose names are misspelt in Strapi, keeping the typos out
+// of the Go API. The JSON tag keeps the schema name.
+var fieldNames = map[string]string{
+	"MinimumDownPaymet":    "MinimumDownPayment",
+	"MinimuDownpayment":    "MinimumDownPayment",
+	"MinimumInstallements": "MinimumInstallments",
+}
+
+// boundTypes maps GraphQL object types to hand-written Go types in the cms package
+// instead of generating a struct per selection
+var boundTypes = map[string]string{
+	"UploadFile": "strapiMediaField",
+}
+
+// initialisms are rendered upper-case when they end a word of a field name
+var initialisms = map[string]string{
+	"Id":  "ID",
+	"Url": "URL",
+}
+
+func main() {
+	schemaPath := flag.String("schema", "schema.gql", "Strapi GraphQL schema (SDL)")
+	queriesPath := flag.String("queries", "queries.go", "Go file declaring the operations as string constants")
+	outPath := flag.String("out", "operations_gen.go", "output file")
+	pkg := flag.String("package", "cms", "package of the output file")
+	flag.Parse()
+
+	log.SetFlags(0)
+	log.SetPrefix("gqltypes: ")
+
+	source, err := generate(*schemaPath, *queriesPath, *pkg)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := os.WriteFile(*outPath, source, 0o644); err != nil {
+		log.Fatalf("failed to write %s: %v", *outPath, err)
+	}
+}
+
+// generate returns the formatted source of the result types for the operations declared
+// in queriesPath, validated against the schema at schemaPath
+func generate(schemaPath, queriesPath, pkg string) ([]byte, error) {
+	schemaBytes, err := os.ReadFile(schemaPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read schema: %w", err)
+	}
+	schema, gqlErr := gqlparser.LoadSchema(&gqlast.Source{Name: schemaPath, Input: string(schemaBytes)})
+	if gqlErr != nil {
+		return nil, fmt.Errorf("failed to load schema: %w", gqlErr)
+	}
+
+	queries, err := readQueries(queriesPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read queries: %w", err)
+	}
+
+	g := &generator{schema: schema, declared: make(map[string]bool)}
+	for _, q := range queries {
+		if err := g.operation(q); err != nil {
+			return nil, fmt.Errorf("%s: %w", q.constName, err)
+		}
+	}
+
+	source, err := g.render(pkg, schemaPath, queriesPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to format output: %w", err)
+	}
+	return source, nil
+}
+
+// query is a GraphQL document declared as a Go string constant
+type query struct {
+	constName string
+	document  string
+}
+
+// readQueries returns the string constants of a Go file that hold a GraphQL query, in file order
+func readQueries(path string) ([]query, error) {
+	file, err := parser.ParseFile(token.NewFileSet(), path, nil, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	var queries []query
+	for _, decl := range file.Decls {
+		genDecl, ok := decl.(*ast.GenDecl)
+		if !ok || genDecl.Tok != token.CONST {
+			continue
+		}
+		for _, spec := range genDecl.Specs {
+			valueSpec := spec.(*ast.ValueSpec)
+			for i, name := range valueSpec.Names {
+				if i >= len(valueSpec.Values) {
+					continue
+				}
+				lit, ok := valueSpec.Values[i].(*ast.BasicLit)
+				if !ok || lit.Kind != token.STRING {
+					continue
+				}
+				document, err := strconv.Unquote(lit.Value)
+				if err != nil {
+					return nil, fmt.Errorf("%s: %w", name.Name, err)
+				}
+				if strings.HasPrefix(strings.TrimSpace(document), "query") {
+					queries = append(queries, query{constName: name.Name, document: document})
+				}
+			}
+		}
+	}
+	return queries, nil
+}
+
+// generator accumulates the struct declarations of all operations
+type generator struct {
+	schema   *gqlast.Schema
+	decls    []string
+	declared map[string]bool
+}
+
+// operation declares the result struct of one query and the structs of its nested selections
+func (g *generator) operation(q query) error {
+	doc, errs := gqlparser.LoadQuery(g.schema, q.document)
+	if len(errs) > 0 {
+		return errs
+	}
+	if len(doc.Operations) != 1 || doc.Operations[0].Name == "" {
+		return fmt.Errorf("expected exactly one named operation")
+	}
+
+	op := doc.Operations[0]
+	prefix := lowerFirst(op.Name)
+	return g.object(prefix+"Result", prefix, fmt.Sprintf("%s is the data of the %s operation (%s)", prefix+"Result", op.Name, q.constName), op.SelectionSet)
+}
+
+// object declares a struct for a selection set; nested selections are named after their path
+func (g *generator) object(typeName, pathPrefix, doc string, selections gqlast.SelectionSet) error {
+	if g.declared[typeName] {
+		return fmt.Errorf("type %s declared twice", typeName)
+	}
+	g.declared[typeName] = true
+
+	var body strings.Builder
+	fmt.Fprintf(&body, "// %s\ntype %s struct {\n", doc, typeName)
+	for _, selection := range selections {
+		field, ok := selection.(*gqlast.Field)
+		if !ok {
+			return fmt.Errorf("%s: only plain field selections are supported", typeName)
+		}
+		if field.Name == "__typename" {
+			fmt.Fprintf(&body, "\tTypename string `json:\"__typename\"`\n")
+			continue
+		}
+
+		fieldName := goName(field.Alias)
+		if renamed, ok := fieldNames[field.Alias]; ok {
+			fieldName = renamed
+		}
+		goType, err := g.fieldType(field, pathPrefix+fieldName)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(&body, "\t%s %s `json:%q`\n", fieldName, goType, field.Alias)
+	}
+	body.WriteString("}\n")
+
+	g.decls = append(g.decls, body.String())
+	return nil
+}
+
+// fieldType returns the Go type of a selected field, declaring a struct for object selections
+func (g *generator) fieldType(field *gqlast.Field, nestedName string) (string, error) {
+	fieldType := field.Definition.Type
+	typeName := fieldType.Name()
+	definition := g.schema.Types[typeName]
+	if definition == nil {
+		return "", fmt.Errorf("unknown type %s", typeName)
+	}
+
+	var elem string
+	switch definition.Kind {
+	case gqlast.Scalar:
+		goType, ok := scalarTypes[typeName]
+		if !ok {
+			return "", fmt.Errorf("no Go type for scalar %s", typeName)
+		}
+		elem = goType
+	case gqlast.Enum:
+		elem = "string"
+	case gqlast.Object:
+		if bound, ok := boundTypes[typeName]; ok {
+			elem = bound
+			break
+		}
+		doc := fmt.Sprintf("%s is the %s selected at %s", nestedName, typeName, field.Alias)
+		if err := g.object(nestedName, nestedName, doc, field.SelectionSet); err != nil {
+			return "", err
+		}
+		elem = nestedName
+	default:
+		return "", fmt.Errorf("field %s: %s types are not supported", field.Alias, definition.Kind)
+	}
+
+	// Lists hold values. Single objects are always pointers: Strapi returns null even for
+	// required media, relations and components of entries saved before they were required.
+	prefix := ""
+	for t := fieldType; t.Elem != nil; t = t.Elem {
+		prefix += "[]"
+	}
+	if prefix == "" && definition.Kind == gqlast.Object {
+		prefix = "*"
+	}
+	if prefix == "" && !fieldType.NonNull && nullableScalars[typeName] {
+		prefix = "*"
+	}
+	return prefix + elem, nil
+}
+
+// render formats the generated file
+func (g *generator) render(pkg, schemaPath, queriesPath string) ([]byte, error) {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "// Code generated by gqltypes from %s and %s; DO NOT EDIT.\n\n", lastElem(schemaPath), lastElem(queriesPath))
+	fmt.Fprintf(&buf, "package %s\n\n", pkg)
+
+	decls := append([]string(nil), g.decls...)
+	sort.Strings(decls)
+	for _, decl := range decls {
+		buf.WriteString(decl)
+		buf.WriteString("\n")
+	}
+	return format.Source(buf.Bytes())
+}
+
+// goName turns a GraphQL field name (documentId, car_model, carVariants_connection)
+// into an exported Go identifier (DocumentID, CarModel, CarVariantsConnection)
+func goName(name string) string {
+	var words []string
+	for _, part := range strings.Split(name, "_") {
+		if part == "" {
+			continue
+		}
+		words = append(words, splitWords(upperFirst(part))...)
+	}
+	for i, word := range words {
+		if initialism, ok := initialisms[word]; ok {
+			words[i] = initialism
+		}
+	}
+	return strings.Join(words, "")
+}
+
+// splitWords splits a camel-case identifier at each upper-case letter that follows a lower-case one
+func splitWords(s string) []string {
+	var words []string
+	runes := []rune(s)
+	start := 0
+	for i := 1; i < len(runes); i++ {
+		if unicode.IsUpper(runes[i]) && unicode.IsLower(runes[i-1]) {
+			words = append(words, string(runes[start:i]))
+			start = i
+		}
+	}
+	return append(words, string(runes[start:]))
+}
+
+func upperFirst(s string) string {
+	if s == "" {
+		return s
+	}
+	return strings.ToUpper(s[:1]) + s[1:]
+}
+
+func lowerFirst(s string) string {
+	if s == "" {
+		return s
+	}
+	return strings.ToLower(s[:1]) + s[1:]
+}
+
+func lastElem(path string) string {
+	return path[strings.LastIndexAny(path, `/\`)+1:]
+}
@@ -0,0 +1,44 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"testing"
+)
+
+// TestOperationsGenUpToDate fails when operations_gen.go differs from what go generate
+// would write for the current schema.gql and queries.go
+func TestOperationsGenUpToDate(t *testing.T) {
+	want, err := generate("../../../../schema.gql", "../../queries.go", "cms")
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := os.ReadFile("../../operations_gen.go")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Error("operations_gen.go is out of date, run go generate ./services/cms")
+	}
+}
+
+func TestGoName(t *testing.T) {
+	tests := []struct {
+		name string
+		want string
+	}{
+		{"documentId", "DocumentID"},
+		{"car_model", "CarModel"},
+		{"carVariants_connection", "CarVariantsConnection"},
+		{"url", "URL"},
+		{"Name", "Name"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := goName(tt.name); got != tt.want {
+				t.Errorf("goName(%q) = %q, want %q", tt.name, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,136 @@
+// Package media turns Strapi upload files into the gateway's media fields
+package media
+
+import (
+	"api-gateway/services/cms/models"
+	"context"
+)
+
+// File is a Strapi upload file (UploadFile) as selected by the CMS queries
+type File struct {
+	DocumentID      string            `json:"documentId"`
+	URL             string            `json:"url"`
+	Width           int               `json:"width"`
+	Height          int               `json:"height"`
+	AlternativeText string            `json:"alternativeText"`
+	Caption         string            `json:"caption"`
+	Mime            string            `json:"mime"`
+	Size            float64           `json:"size"` // Kilobytes, as reported by Strapi
+	UpdatedAt       string            `json:"updatedAt"`
+	Formats         map[string]Format `json:"formats"`
+}
+
+// Format is one entry of an upload file's formats JSON, i.e. a resized copy generated
+// by Strapi for a breakpoint (thumbnail, small, medium, large or a custom one)
+type Format struct {
+	URL    string  `json:"url"`
+	Width  int     `json:"width"`
+	Height int     `json:"height"`
+	Mime   string  `json:"mime"`
+	Size   float64 `json:"size"` // Kilobytes
+}
+
+// Transformer converts upload files to media fields, rewriting every URL
+type Transformer struct {
+	rewriter     URLRewriter
+	placeholders *Placeholders
+}
+
+// NewTransformer creates a transformer that rewrites URLs with the given rewriter.
+// A nil rewriter leaves URLs as returned by Strapi.
+func NewTransformer(rewriter URLRewriter) *Transformer {
+	if rewriter == nil {
+		rewriter = URLRewriterFunc(func(url string) string { return url })
+	}
+	return &Transformer{rewriter: rewriter}
+}
+
+// WithPlaceholders makes the transformer include BlurHash placeholders and dominant colors
+// from the given store in media fields
+func (t *Transformer) WithPlaceholders(placeholders *Placeholders) *Transformer {
+	t.placeholders = placeholders
+	return t
+}
+
+// Files returns the images in a GraphQL response that get placeholders, to be kept with the
+// cached response and passed to Prefetch; it returns nil without placeholders
+func (t *Transformer) Files(data []byte) []FileVersion {
+	if t.placeholders == nil {
+		return nil
+	}
+	return responseFiles(data)
+}
+
+// Prefetch loads the placeholders of a response's images (see Files) in one cache read, so
+// that Field does not wait for them. Nothing is read when all of them are loaded.
+func (t *Transformer) Prefetch(ctx context.Context, files []FileVersion) {
+	if t.placeholders == nil || len(files) == 0 {
+		return
+	}
+	t.placeholders.Prefetch(ctx, files)
+}
+
+// RewriteURL rewrites a single media URL, e.g. one stored outside an upload file
+func (t *Transformer) RewriteURL(url string) string {
+	return t.rewriteVersioned(url, "")
+}
+
+// rewriteVersioned rewrites a URL of a file with a known version (its updatedAt)
+func (t *Transformer) rewriteVersioned(url, version string) string {
+	if url == "" {
+		return ""
+	}
+	if versioned, ok := t.rewriter.(VersionedURLRewriter); ok && version != "" {
+		return versioned.RewriteVersionedURL(url, version)
+	}
+	return t.rewriter.RewriteURL(url)
+}
+
+// Field converts an upload file to a media field with all its formats; nil stays nil
+func (t *Transformer) Field(file *File) *models.MediaField {
+	if file == nil {
+		return nil
+	}
+
+	field := &models.MediaField{
+		ID:              file.DocumentID,
+		Width:           file.Width,
+		Height:          file.Height,
+		URL:             t.rewriteVersioned(file.URL, file.UpdatedAt),
+		AlternativeText: file.AlternativeText,
+		Caption:         file.Caption,
+		Mime:            file.Mime,
+		Size:            file.Size,
+	}
+
+	if t.placeholders != nil {
+		if placeholder := t.placeholders.Lookup(file); placeholder != nil {
+			field.BlurHash = placeholder.BlurHash
+			field.DominantColor = placeholder.DominantColor
+		}
+	}
+
+	if len(file.Formats) > 0 {
+		field.Formats = make(models.MediaFormats, len(file.Formats))
+		for name, format := range file.Formats {
+			field.Formats[name] = &models.MediaFormat{
+				Width:  format.Width,
+				Height: format.Height,
+				URL:    t.rewriteVersioned(format.URL, file.UpdatedAt),
+				Mime:   format.Mime,
+				Size:   format.Size,
+			}
+		}
+	}
+
+	return field
+}
+
+// Collection converts a list of upload files
+func (t *Transformer) Collection(files []File) models.MediaCollectionField {
+	collection := make(models.MediaCollectionField, len(files))
+	for i := range files {
+		collection[i] = *t.Field(&files[i])
+	}
+	return collection
+}
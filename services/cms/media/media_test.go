@@ -0,0 +1,136 @@
+package media
+
+import (
+	"api-gateway/services/cms/models"
+	"reflect"
+	"testing"
+)
+
+func TestTransformerField(t *testing.T) {
+	file := &File{
+		DocumentID:      "img1",
+		URL:             "/uploads/car.jpg",
+		Width:           1920,
+		Height:          1080,
+		AlternativeText: "Front view",
+		Caption:         "2025 model",
+		Mime:            "image/jpeg",
+		Size:            512.5,
+		UpdatedAt:       "2025-01-02T03:04:05.000Z",
+		Formats: map[string]Format{
+			"thumbnail": {URL: "/uploads/thumbnail_car.jpg", Width: 245, Height: 138, Mime: "image/jpeg", Size: 9.1},
+			"xlarge":    {URL: "/uploads/xlarge_car.jpg", Width: 1600, Height: 900, Mime: "image/jpeg", Size: 301.2},
+		},
+	}
+	bust := "?v=" + versionHash(file.UpdatedAt)
+
+	tests := []struct {
+		name        string
+		rewriter    URLRewriter
+		wantURL     string
+		wantFormats map[string]string // format name -> URL
+	}{
+		{
+			name:        "nil rewriter keeps Strapi URLs",
+			rewriter:    nil,
+			wantURL:     "/uploads/car.jpg",
+			wantFormats: map[string]string{"thumbnail": "/uploads/thumbnail_car.jpg", "xlarge": "/uploads/xlarge_car.jpg"},
+		},
+		{
+			name:        "prefix rewriter",
+			rewriter:    PrefixRewriter{BaseURL: "https://api.example.com/"},
+			wantURL:     "https://api.example.com/uploads/car.jpg",
+			wantFormats: map[string]string{"thumbnail": "https://api.example.com/uploads/thumbnail_car.jpg", "xlarge": "https://api.example.com/uploads/xlarge_car.jpg"},
+		},
+		{
+			name:        "versioned rewriter gets the file's updatedAt for every format",
+			rewriter:    &URLStrategy{BaseURL: "https://cdn.example.com", PathMap: map[string]string{"/uploads/": "/media/"}, CacheBust: true},
+			wantURL:     "https://cdn.example.com/media/car.jpg" + bust,
+			wantFormats: map[string]string{"thumbnail": "https://cdn.example.com/media/thumbnail_car.jpg" + bust, "xlarge": "https://cdn.example.com/media/xlarge_car.jpg" + bust},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			field := NewTransformer(tt.rewriter).Field(file)
+
+			want := &models.MediaField{
+				ID:              "img1",
+				Width:           1920,
+				Height:          1080,
+				URL:             tt.wantURL,
+				AlternativeText: "Front view",
+				Caption:         "2025 model",
+				Mime:            "image/jpeg",
+				Size:            512.5,
+				Formats: models.MediaFormats{
+					"thumbnail": {Width: 245, Height: 138, URL: tt.wantFormats["thumbnail"], Mime: "image/jpeg", Size: 9.1},
+					"xlarge":    {Width: 1600, Height: 900, URL: tt.wantFormats["xlarge"], Mime: "image/jpeg", Size: 301.2},
+				},
+			}
+			if !reflect.DeepEqual(field, want) {
+				t.Errorf("Field() = %+v, want %+v", field, want)
+				for name, format := range field.Formats {
+					t.Logf("format %s: %+v", name, format)
+				}
+			}
+		})
+	}
+}
+
+func TestTransformerFieldEmpty(t *testing.T) {
+	transformer := NewTransformer(PrefixRewriter{BaseURL: "https://api.example.com"})
+
+	if field := transformer.Field(nil); field != nil {
+		t.Errorf("Field(nil) = %+v, want nil", field)
+	}
+
+	field := transformer.Field(&File{DocumentID: "doc1"})
+	if field.URL != "" {
+		t.Errorf("URL = %q, want empty URLs not to be rewritten", field.URL)
+	}
+	if field.Formats != nil {
+		t.Errorf("Formats = %v, want nil without formats", field.Formats)
+	}
+}
+
+func TestTransformerCollection(t *testing.T) {
+	transformer := NewTransformer(PrefixRewriter{BaseURL: "https://api.example.com"})
+	files := []File{
+		{DocumentID: "a", URL: "/uploads/a.jpg"},
+		{DocumentID: "b", URL: "https://s3.example.com/b.jpg"},
+	}
+
+	collection := transformer.Collection(files)
+	if len(collection) != 2 {
+		t.Fatalf("len = %d, want 2", len(collection))
+	}
+	if collection[0].ID != "a" || collection[0].URL != "https://api.example.com/uploads/a.jpg" {
+		t.Errorf("collection[0] = %+v", collection[0])
+	}
+	if collection[1].URL != "https://s3.example.com/b.jpg" {
+		t.Errorf("collection[1].URL = %q, want absolute URLs unchanged", collection[1].URL)
+	}
+}
+
+func TestPrefixRewriter(t *testing.T) {
+	tests := []struct {
+		baseURL string
+		url     string
+		want    string
+	}{
+		{"https://api.example.com", "/uploads/a.jpg", "https://api.example.com/uploads/a.jpg"},
+		{"https://api.example.com/", "/uploads/a.jpg", "https://api.example.com/uploads/a.jpg"},
+		{"https://api.example.com", "uploads/a.jpg", "https://api.example.com/uploads/a.jpg"},
+		{"https://api.example.com", "https://s3.example.com/a.jpg", "https://s3.example.com/a.jpg"},
+		{"https://api.example.com", "http://legacy.example.com/a.jpg", "http://legacy.example.com/a.jpg"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.baseURL+" "+tt.url, func(t *testing.T) {
+			if got := (PrefixRewriter{BaseURL: tt.baseURL}).RewriteURL(tt.url); got != tt.want {
+				t.Errorf("RewriteURL(%q) = %q, want %q", tt.url, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,300 @@
+package media
+
+import (
+	"api-gateway/pkg/cache"
+	"api-gateway/pkg/imaging"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// maxPlaceholderSourceBytes bounds the images downloaded to compute a placeholder
+const maxPlaceholderSourceBytes = 5 << 20
+
+// PlaceholdersConfig holds the settings of a Placeholders store
+type PlaceholdersConfig struct {
+	BaseURL     string        // Strapi base URL that relative upload URLs are fetched from
+	Cache       cache.Cache   // Where computed placeholders are kept
+	TTL         time.Duration // Default 30 days
+	Concurrency int           // Placeholders computed at once (default 4); further misses wait for a later request
+	MaxLoaded   int           // Placeholders kept in memory (default 10000); the least recently used are dropped
+}
+
+// Placeholders computes BlurHash placeholders and dominant colors of images from their
+// thumbnail format, once per documentId, and keeps them in the cache. Placeholders read
+// from the cache are kept in an in-memory LRU too (a few dozen bytes per image), so
+// lookups never do I/O: Prefetch loads the placeholders of a whole response in one round
+// trip, and a placeholder still missing is loaded or computed in the background and
+// appears in responses once it is ready.
+type Placeholders struct {
+	baseURL    string
+	cache      cache.Cache
+	ttl        time.Duration
+	httpClient *http.Client
+	slots      chan struct{}
+	pending    sync.Map           // documentIds being computed
+	loaded     *cache.MemoryCache // documentId -> placeholderEntry JSON read from or written to the cache
+}
+
+// FileVersion identifies an image by documentId and version (its updatedAt). Lists of them
+// are kept with cached responses, so their placeholders can be prefetched without parsing
+// the response again.
+type FileVersion struct {
+	DocumentID string `json:"documentId"`
+	Version    string `json:"version"`
+}
+
+// placeholderEntry is a cached placeholder; an empty one records an image that could not be decoded
+type placeholderEntry struct {
+	Version string `json:"version"` // updatedAt of the file it was computed from
+	imaging.Placeholder
+}
+
+// NewPlaceholders creates a placeholder store
+func NewPlaceholders(config PlaceholdersConfig) *Placeholders {
+	if config.Cache == nil {
+		config.Cache = &cache.NoOpCache{}
+	}
+	if config.TTL == 0 {
+		config.TTL = 30 * 24 * time.Hour
+	}
+	if config.Concurrency == 0 {
+		config.Concurrency = 4
+	}
+	if config.MaxLoaded == 0 {
+		config.MaxLoaded = 10000
+	}
+	return &Placeholders{
+		baseURL:    strings.TrimSuffix(config.BaseURL, "/"),
+		cache:      config.Cache,
+		ttl:        config.TTL,
+		httpClient: &http.Client{Timeout: 10 * time.Second},
+		slots:      make(chan struct{}, config.Concurrency),
+		loaded:     cache.NewMemoryCache(cache.MemoryCacheConfig{MaxEntries: config.MaxLoaded}),
+	}
+}
+
+// Lookup returns the placeholder of an image, or nil if the file is not an image or its
+// placeholder is not loaded yet (it is then loaded or computed in the background)
+func (p *Placeholders) Lookup(file *File) *imaging.Placeholder {
+	if file == nil || file.DocumentID == "" || !placeholderCandidate(file.Mime) {
+		return nil
+	}
+
+	if entry, ok := p.entry(file.DocumentID, file.UpdatedAt); ok {
+		if entry.BlurHash == "" {
+			return nil
+		}
+		return &entry.Placeholder
+	}
+
+	p.schedule(file)
+	return nil
+}
+
+// Prefetch loads the cached placeholders of files not loaded yet in a single cache read
+func (p *Placeholders) Prefetch(ctx context.Context, files []FileVersion) {
+	var keys []string
+	for _, file := range files {
+		if _, ok := p.entry(file.DocumentID, file.Version); !ok {
+			keys = append(keys, placeholderCacheKey(file.DocumentID))
+		}
+	}
+	if len(keys) == 0 {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 100*time.Millisecond)
+	defer cancel()
+	values, err := cache.GetMulti(ctx, p.cache, keys)
+	if err != nil {
+		return
+	}
+	for key, data := range values {
+		var entry placeholderEntry
+		if err := json.Unmarshal(data, &entry); err == nil {
+			p.remember(strings.TrimPrefix(key, placeholderCacheKey("")), data)
+		}
+	}
+}
+
+// entry returns the loaded placeholder of a file if it was computed from the given version
+func (p *Placeholders) entry(documentID, version string) (placeholderEntry, bool) {
+	data, _ := p.loaded.Get(context.Background(), documentID)
+	if data == nil {
+		return placeholderEntry{}, false
+	}
+	var entry placeholderEntry
+	if err := json.Unmarshal(data, &entry); err != nil {
+		return placeholderEntry{}, false
+	}
+	return entry, entry.Version == version
+}
+
+// remember keeps a placeholder entry's JSON in memory
+func (p *Placeholders) remember(documentID string, data []byte) {
+	_ = p.loaded.Set(context.Background(), documentID, data, 0)
+}
+
+// schedule loads a placeholder from the cache, or computes it, in the background unless
+// the file is already being handled or all slots are busy
+func (p *Placeholders) schedule(file *File) {
+	if _, busy := p.pending.LoadOrStore(file.DocumentID, struct{}{}); busy {
+		return
+	}
+	select {
+	case p.slots <- struct{}{}:
+	default:
+		p.pending.Delete(file.DocumentID)
+		return
+	}
+
+	source := sourceURL(file)
+	documentID, version := file.DocumentID, file.UpdatedAt
+	go func() {
+		defer func() {
+			<-p.slots
+			p.pending.Delete(documentID)
+		}()
+
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+
+		// Another instance may have computed it already
+		if data, err := p.cache.Get(ctx, placeholderCacheKey(documentID)); err == nil && data != nil {
+			var entry placeholderEntry
+			if err := json.Unmarshal(data, &entry); err == nil && entry.Version == version {
+				p.remember(documentID, data)
+				return
+			}
+		}
+
+		entry := placeholderEntry{Version: version}
+		placeholder, err := p.compute(ctx, source)
+		if err != nil {
+			log.Printf("Failed to compute placeholder for %s: %v", documentID, err)
+			if !errors.Is(err, imaging.ErrUnsupported) {
+				return // Transient: try again on a later request
+			}
+		} else {
+			entry.Placeholder = *placeholder
+		}
+
+		data, err := json.Marshal(entry)
+		if err != nil {
+			return
+		}
+		if err := p.cache.Set(ctx, placeholderCacheKey(documentID), data, p.ttl); err != nil {
+			log.Printf("Failed to cache placeholder for %s: %v", documentID, err)
+		}
+		p.remember(documentID, data)
+	}()
+}
+
+// compute downloads an image and computes its placeholder
+func (p *Placeholders) compute(ctx context.Context, url string) (*imaging.Placeholder, error) {
+	if !isAbsolute(url) {
+		url = p.baseURL + "/" + strings.TrimPrefix(url, "/")
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := p.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%s responded with status %d", url, resp.StatusCode)
+	}
+
+	source, err := io.ReadAll(io.LimitReader(resp.Body, maxPlaceholderSourceBytes+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(source) > maxPlaceholderSourceBytes {
+		return nil, fmt.Errorf("%w: %s is larger than %d bytes", imaging.ErrUnsupported, url, maxPlaceholderSourceBytes)
+	}
+	return imaging.NewPlaceholder(source)
+}
+
+// sourceURL picks the image a placeholder is computed from: the thumbnail format, or the
+// original when Strapi made none (images smaller than the thumbnail size)
+func sourceURL(file *File) string {
+	if thumbnail, ok := file.Formats["thumbnail"]; ok && thumbnail.URL != "" {
+		return thumbnail.URL
+	}
+	return file.URL
+}
+
+// placeholderCandidate reports whether files of a MIME type get placeholders
+func placeholderCandidate(mime string) bool {
+	return strings.HasPrefix(mime, "image/") && mime != "image/svg+xml"
+}
+
+// placeholderCacheKey is the cache key of a file's placeholder. The CMS cache adds its own
+// "cms:" prefix.
+func placeholderCacheKey(documentID string) string {
+	return "media:placeholder:" + documentID
+}
+
+// responseFiles returns the images that get placeholders in a GraphQL response: every
+// object with a documentId and an image mime field
+func responseFiles(data []byte) []FileVersion {
+	if !bytes.Contains(data, []byte(`"mime"`)) {
+		return nil
+	}
+	var files []FileVersion
+	for _, file := range appendFiles(nil, data) {
+		if file.DocumentID != "" && placeholderCandidate(file.Mime) {
+			files = append(files, FileVersion{DocumentID: file.DocumentID, Version: file.UpdatedAt})
+		}
+	}
+	return files
+}
+
+// appendFiles appends the upload files found in a JSON value
+func appendFiles(files []*File, value json.RawMessage) []*File {
+	value = bytes.TrimSpace(value)
+	if len(value) == 0 {
+		return files
+	}
+
+	switch value[0] {
+	case '[':
+		var items []json.RawMessage
+		if err := json.Unmarshal(value, &items); err != nil {
+			return files
+		}
+		for _, item := range items {
+			files = appendFiles(files, item)
+		}
+	case '{':
+		var fields map[string]json.RawMessage
+		if err := json.Unmarshal(value, &fields); err != nil {
+			return files
+		}
+		_, hasMime := fields["mime"]
+		_, hasDocumentID := fields["documentId"]
+		if hasMime && hasDocumentID {
+			var file File
+			if err := json.Unmarshal(value, &file); err == nil {
+				files = append(files, &file)
+			}
+			return files
+		}
+		for _, field := range fields {
+			files = appendFiles(files, field)
+		}
+	}
+	return files
+}
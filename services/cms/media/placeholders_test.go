@@ -0,0 +1,115 @@
+package media
+
+import (
+	"api-gateway/pkg/cache"
+	"context"
+	"encoding/json"
+	"reflect"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// countingCache counts the reads that reach a cache
+type countingCache struct {
+	cache.Cache
+	reads atomic.Int64
+}
+
+func (c *countingCache) Get(ctx context.Context, key string) ([]byte, error) {
+	c.reads.Add(1)
+	return c.Cache.Get(ctx, key)
+}
+
+// newTestPlaceholders returns a placeholder store over an in-memory cache seeded with the
+// placeholders of documentIds, all computed from version v1
+func newTestPlaceholders(t *testing.T, maxLoaded int, documentIDs ...string) (*Placeholders, *countingCache) {
+	t.Helper()
+	backing := &countingCache{Cache: cache.NewMemoryCache(cache.MemoryCacheConfig{})}
+	for _, documentID := range documentIDs {
+		data, err := json.Marshal(placeholderEntry{Version: "v1"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		backing.Cache.Set(context.Background(), placeholderCacheKey(documentID), data, time.Hour)
+	}
+	return NewPlaceholders(PlaceholdersConfig{Cache: backing, MaxLoaded: maxLoaded}), backing
+}
+
+func TestResponseFiles(t *testing.T) {
+	data := []byte(`{
+		"brand": {
+			"documentId": "bmw",
+			"Logo": {"documentId": "logo", "mime": "image/png", "updatedAt": "v1", "url": "/uploads/logo.png"},
+			"Icon": {"documentId": "icon", "mime": "image/svg+xml", "updatedAt": "v1"},
+			"Brochure": {"documentId": "pdf", "mime": "application/pdf", "updatedAt": "v1"},
+			"car_models": [
+				{"Gallery": [
+					{"documentId": "g1", "mime": "image/jpeg", "updatedAt": "v2"},
+					{"mime": "image/jpeg", "updatedAt": "v1"}
+				]}
+			]
+		}
+	}`)
+
+	got := responseFiles(data)
+	want := map[FileVersion]bool{{"logo", "v1"}: true, {"g1", "v2"}: true}
+	if len(got) != len(want) {
+		t.Fatalf("responseFiles() = %v, want %v", got, want)
+	}
+	for _, file := range got {
+		if !want[file] {
+			t.Errorf("unexpected file %v", file)
+		}
+	}
+
+	if files := responseFiles([]byte(`{"brands":[{"documentId":"bmw"}]}`)); files != nil {
+		t.Errorf("responseFiles() without media = %v, want nil", files)
+	}
+}
+
+func TestPlaceholdersPrefetchReadsOnlyMissing(t *testing.T) {
+	placeholders, backing := newTestPlaceholders(t, 0, "a", "b")
+	ctx := context.Background()
+	files := []FileVersion{{"a", "v1"}, {"b", "v1"}}
+
+	placeholders.Prefetch(ctx, files)
+	if reads := backing.reads.Load(); reads != 2 {
+		t.Fatalf("first Prefetch read %d keys, want 2", reads)
+	}
+
+	// Everything is loaded: no cache read at all
+	placeholders.Prefetch(ctx, files)
+	if reads := backing.reads.Load(); reads != 2 {
+		t.Errorf("Prefetch of loaded files read the cache %d more times", reads-2)
+	}
+
+	// A new version of b is read again
+	placeholders.Prefetch(ctx, []FileVersion{{"a", "v1"}, {"b", "v2"}})
+	if reads := backing.reads.Load(); reads != 3 {
+		t.Errorf("Prefetch after b changed read %d keys, want 1", reads-2)
+	}
+}
+
+func TestPlaceholdersLoadedIsBounded(t *testing.T) {
+	placeholders, _ := newTestPlaceholders(t, 2, "a", "b", "c")
+
+	placeholders.Prefetch(context.Background(), []FileVersion{{"a", "v1"}, {"b", "v1"}, {"c", "v1"}})
+
+	stats := placeholders.loaded.Stats()
+	if stats.Entries != 2 || stats.Evictions != 1 {
+		t.Errorf("loaded stats = %+v, want 2 entries and 1 eviction", stats)
+	}
+}
+
+func TestTransformerFilesWithoutPlaceholders(t *testing.T) {
+	data := []byte(`{"logo":{"documentId":"logo","mime":"image/png","updatedAt":"v1"}}`)
+
+	if files := NewTransformer(nil).Files(data); files != nil {
+		t.Errorf("Files() without placeholders = %v, want nil", files)
+	}
+	placeholders, _ := newTestPlaceholders(t, 0)
+	if files := NewTransformer(nil).WithPlaceholders(placeholders).Files(data); !reflect.DeepEqual(files, []FileVersion{{"logo", "v1"}}) {
+		t.Errorf("Files() = %v, want the logo", files)
+	}
+}
@@ -0,0 +1,36 @@
+package media
+
+import "strings"
+
+// URLRewriter turns a URL returned by Strapi (usually a relative /uploads path)
+// into the URL sent to clients
+type URLRewriter interface {
+	RewriteURL(url string) string
+}
+
+// URLRewriterFunc adapts a function to URLRewriter
+type URLRewriterFunc func(url string) string
+
+// RewriteURL calls f(url)
+func (f URLRewriterFunc) RewriteURL(url string) string {
+	return f(url)
+}
+
+// PrefixRewriter prefixes relative URLs with a base URL and leaves absolute URLs,
+// e.g. files on an external upload provider, unchanged
+type PrefixRewriter struct {
+	BaseURL string
+}
+
+// RewriteURL implements URLRewriter
+func (r PrefixRewriter) RewriteURL(url string) string {
+	if isAbsolute(url) {
+		return url
+	}
+	return strings.TrimSuffix(r.BaseURL, "/") + "/" + strings.TrimPrefix(url, "/")
+}
+
+// isAbsolute reports whether url has an http(s) scheme
+func isAbsolute(url string) bool {
+	return strings.HasPrefix(url, "http://") || strings.HasPrefix(url, "https://")
+}
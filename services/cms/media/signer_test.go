@@ -0,0 +1,80 @@
+package media
+
+import (
+	"errors"
+	"strconv"
+	"testing"
+	"time"
+)
+
+// newTestSigner returns a signer whose clock reads *now
+func newTestSigner(ttl time.Duration, now *time.Time) *Signer {
+	signer := NewSigner([]byte("secret"), ttl)
+	signer.now = func() time.Time { return *now }
+	return signer
+}
+
+func TestSignerSignRoundsExpiryToWindows(t *testing.T) {
+	tests := []struct {
+		name        string
+		now         int64
+		wantExpires int64
+	}{
+		{"start of a window", 7200, 14400},
+		{"end of a window", 10799, 14400},
+		{"next window", 10800, 18000},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			now := time.Unix(tt.now, 0)
+			expires, _ := newTestSigner(time.Hour, &now).Sign("/uploads/a.pdf")
+			if expires != tt.wantExpires {
+				t.Errorf("expires = %d, want %d", expires, tt.wantExpires)
+			}
+		})
+	}
+}
+
+func TestSignerVerify(t *testing.T) {
+	now := time.Unix(1_700_000_000, 0)
+	signer := newTestSigner(time.Hour, &now)
+	expires, signature := signer.Sign("/uploads/a.pdf")
+	expiresParam := strconv.FormatInt(expires, 10)
+
+	tests := []struct {
+		name      string
+		path      string
+		expires   string
+		signature string
+		at        time.Time
+		want      error
+	}{
+		{"valid", "/uploads/a.pdf", expiresParam, signature, now, nil},
+		{"valid until the expiry", "/uploads/a.pdf", expiresParam, signature, time.Unix(expires, 0), nil},
+		{"expired", "/uploads/a.pdf", expiresParam, signature, time.Unix(expires+1, 0), ErrSignatureExpired},
+		{"other path", "/uploads/b.pdf", expiresParam, signature, now, ErrSignatureInvalid},
+		{"extended expiry", "/uploads/a.pdf", strconv.FormatInt(expires+3600, 10), signature, now, ErrSignatureInvalid},
+		{"malformed expiry", "/uploads/a.pdf", "soon", signature, now, ErrSignatureInvalid},
+		{"missing signature", "/uploads/a.pdf", expiresParam, "", now, ErrSignatureMissing},
+		{"missing expiry", "/uploads/a.pdf", "", signature, now, ErrSignatureMissing},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			now = tt.at
+			if err := signer.Verify(tt.path, tt.expires, tt.signature); !errors.Is(err, tt.want) {
+				t.Errorf("Verify() = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestSignerKeyMatters(t *testing.T) {
+	now := time.Unix(1_700_000_000, 0)
+	expires, signature := newTestSigner(time.Hour, &now).Sign("/uploads/a.pdf")
+
+	other := NewSigner([]byte("other secret"), time.Hour)
+	other.now = func() time.Time { return now }
+	if err := other.Verify("/uploads/a.pdf", strconv.FormatInt(expires, 10), signature); !errors.Is(err, ErrSignatureInvalid) {
+		t.Errorf("Verify() with another key = %v, want %v", err, ErrSignatureInvalid)
+	}
+}
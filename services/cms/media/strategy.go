@@ -0,0 +1,128 @@
+package media
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"net/url"
+	"path"
+	"strconv"
+	"strings"
+)
+
+// VersionedURLRewriter is implemented by rewriters that use the version of the file
+// behind a URL (its updatedAt), e.g. for cache busting. The transformer prefers it over
+// RewriteURL when the version is known.
+type VersionedURLRewriter interface {
+	URLRewriter
+	RewriteVersionedURL(url, version string) string
+}
+
+// URLStrategy rewrites Strapi media paths for clients: it maps them onto a public host
+// (the gateway or a CDN) and path, optionally appends a cache-busting version and signs
+// private files. Absolute URLs (external upload providers) are left unchanged.
+type URLStrategy struct {
+	// BaseURL is the public host media is served from, e.g. https://cdn.example.com
+	BaseURL string
+
+	// PathMap rewrites path prefixes, e.g. {"/uploads/": "/media/"} when the CDN serves
+	// Strapi uploads under /media/. The longest matching prefix wins.
+	PathMap map[string]string
+
+	// CacheBust appends v=<hash of the file's updatedAt> so that replaced files get new URLs
+	CacheBust bool
+
+	// Signer, if set, signs the files selected by SignedExtensions
+	Signer *Signer
+
+	// SignedExtensions lists the file extensions to sign, e.g. [".pdf"]; empty signs every file
+	SignedExtensions []string
+}
+
+// RewriteURL implements URLRewriter
+func (s *URLStrategy) RewriteURL(url string) string {
+	return s.RewriteVersionedURL(url, "")
+}
+
+// RewriteVersionedURL implements VersionedURLRewriter
+func (s *URLStrategy) RewriteVersionedURL(rawURL, version string) string {
+	if isAbsolute(rawURL) {
+		return rawURL
+	}
+
+	originPath := "/" + strings.TrimPrefix(rawURL, "/")
+	query := url.Values{}
+	if s.CacheBust && version != "" {
+		query.Set("v", versionHash(version))
+	}
+	// Signatures cover the origin path, so they verify no matter how a CDN maps it
+	if s.RequiresSignature(originPath) {
+		expires, signature := s.Signer.Sign(originPath)
+		query.Set("expires", strconv.FormatInt(expires, 10))
+		query.Set("sig", signature)
+	}
+
+	rewritten := strings.TrimSuffix(s.BaseURL, "/") + s.mapPath(originPath)
+	if len(query) > 0 {
+		rewritten += "?" + query.Encode()
+	}
+	return rewritten
+}
+
+// RequiresSignature reports whether URLs of an origin path (e.g. /uploads/brochure.pdf) are signed,
+// and therefore whether requests for it must carry a valid signature
+func (s *URLStrategy) RequiresSignature(originPath string) bool {
+	if s.Signer == nil {
+		return false
+	}
+	if len(s.SignedExtensions) == 0 {
+		return true
+	}
+	ext := strings.ToLower(path.Ext(originPath))
+	for _, signed := range s.SignedExtensions {
+		if ext == strings.ToLower(signed) {
+			return true
+		}
+	}
+	return false
+}
+
+// VerifyRequest checks the signature of a request for an origin path, given its expires
+// and sig query parameters. Paths that are not signed always pass.
+func (s *URLStrategy) VerifyRequest(originPath, expires, signature string) error {
+	if !s.RequiresSignature(originPath) {
+		return nil
+	}
+	return s.Signer.Verify(originPath, expires, signature)
+}
+
+// mapPath applies the longest matching PathMap prefix
+func (s *URLStrategy) mapPath(originPath string) string {
+	longest := ""
+	for prefix := range s.PathMap {
+		if strings.HasPrefix(originPath, prefix) && len(prefix) > len(longest) {
+			longest = prefix
+		}
+	}
+	if longest == "" {
+		return originPath
+	}
+	return s.PathMap[longest] + strings.TrimPrefix(originPath, longest)
+}
+
+// ParsePathMap parses a comma-separated list of from=to prefixes, e.g. "/uploads/=/media/"
+func ParsePathMap(raw string) map[string]string {
+	pathMap := make(map[string]string)
+	for _, pair := range strings.Split(raw, ",") {
+		from, to, ok := strings.Cut(strings.TrimSpace(pair), "=")
+		if ok && from != "" {
+			pathMap[from] = to
+		}
+	}
+	return pathMap
+}
+
+// versionHash shortens a file version to a URL-friendly token
+func versionHash(version string) string {
+	sum := sha256.Sum256([]byte(version))
+	return hex.EncodeToString(sum[:4])
+}
@@ -6,3 +6,9 @@ type SimpleBrand struct {
 	Title     string      `json:"title"`
 	Thumbnail *MediaField `json:"thumbnail,omitempty"`
 }
+
+// SimpleBrandRef is a simplified reference to a brand
+type SimpleBrandRef struct {
+	ID    string `json:"id"`
+	Title string `json:"title"`
+}
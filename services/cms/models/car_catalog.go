@@ -0,0 +1,33 @@
+package models
+
+// CatalogCar is a car variant in the car catalog
+type CatalogCar struct {
+	ID              string             `json:"id"`
+	Title           string             `json:"title"`
+	Year            int                `json:"year"`
+	Price           int                `json:"price"`
+	MarketPrice     int                `json:"marketprice"`
+	MinDownPayment  int                `json:"mindownpayment"`
+	MinInstallments int                `json:"mininstallments"`
+	BodyType        string             `json:"bodytype,omitempty"`
+	FuelType        string             `json:"fueltype,omitempty"`
+	Thumbnail       *MediaField        `json:"thumbnail,omitempty"`
+	Model           *SimpleCarModelRef `json:"model,omitempty"`
+	Brand           *SimpleBrandRef    `json:"brand,omitempty"`
+
+	MarketPriceDerivation MarketPriceDerivation `json:"marketpricederivation"`
+}
+
+// CarCatalogPage is one page of the car catalog
+type CarCatalogPage struct {
+	Data       []CatalogCar `json:"data"`
+	Pagination Pagination   `json:"pagination"`
+}
+
+// Pagination describes the page of a paginated response
+type Pagination struct {
+	Page      int `json:"page"`
+	PageSize  int `json:"pageSize"`
+	PageCount int `json:"pageCount"`
+	Total     int `json:"total"`
+}
@@ -9,6 +9,8 @@ type SimpleCarModel struct {
 	PriceTo         int         `json:"priceto"`
 	MarketPriceFrom int         `json:"marketpricefrom"`
 	MarketPriceTo   int         `json:"marketpriceto"`
+
+	MarketPriceDerivations []MarketPriceDerivation `json:"marketpricederivations,omitempty"`
 }
 
 // DetailedCarModel is a detailed car model response with all variants and showrooms
@@ -27,6 +29,15 @@ type DetailedCarModel struct {
 	Showrooms       []SimpleShowroom        `json:"showrooms"`
 	Reviews         []ReviewItem            `json:"reviews"`
 	Catalogs        []CatalogItem           `json:"catalogs"`
+
+	MarketPriceDerivations []MarketPriceDerivation `json:"marketpricederivations,omitempty"`
+}
+
+// MarketPriceDerivation describes how market prices were derived by the pricing rules
+type MarketPriceDerivation struct {
+	Method string  `json:"method"`          // list, percent, absolute or showroom-median
+	Value  float64 `json:"value,omitempty"` // Markup of percent and absolute rules
+	Rule   string  `json:"rule,omitempty"`  // Name of the configured rule
 }
 
 // SimpleVariant represents a simplified variant in the detailed car model response
@@ -78,6 +89,8 @@ type DetailedVariant struct {
 	Catalog         *CatalogItem            `json:"catalog,omitempty"`
 	Specs           []SpecItem              `json:"specs"`
 	Features        []FeatureItem           `json:"features"`
+
+	MarketPriceDerivations []MarketPriceDerivation `json:"marketpricederivations,omitempty"`
 }
 
 // SimpleCarModelRef is a simplified reference to a car model
@@ -86,11 +99,19 @@ type SimpleCarModelRef struct {
 	Title string `json:"title"`
 }
 
-// SpecItem represents a specification item
+// SpecItem represents a specification item, described by the spec catalog (services/cms/specs)
 type SpecItem struct {
 	ID    int    `json:"id"`
-	Label string `json:"label"`
-	Value string `json:"value"`
+	Label string `json:"label"` // In the request locale
+	Value string `json:"value"` // Formatted for display, with its unit, e.g. 1,850 mm
+
+	Key        string   `json:"key"`              // Stable across locales, e.g. groundClearance
+	Type       string   `json:"type"`             // number, enum or text
+	Group      string   `json:"group"`            // engine, performance, dimensions, capacity or origin
+	GroupLabel string   `json:"grouplabel"`       // In the request locale
+	Unit       string   `json:"unit,omitempty"`   // Unit of number specs, e.g. mm
+	Number     *float64 `json:"number,omitempty"` // Value of number specs, in Unit
+	Enum       string   `json:"enum,omitempty"`   // Canonical value of enum specs, e.g. automatic; empty for values not in the catalog
 }
 
 // FeatureItem represents a feature item
@@ -0,0 +1,28 @@
+package models
+
+// VariantComparison is a side-by-side comparison of car variants. Every row holds one
+// value per variant, in the order of Variants.
+type VariantComparison struct {
+	Variants []ComparedVariant `json:"variants"`
+	Specs    []ComparisonRow   `json:"specs"`
+	Features []ComparisonRow   `json:"features"`
+}
+
+// ComparedVariant is a variant in a comparison
+type ComparedVariant struct {
+	ID               string             `json:"id"`
+	Title            string             `json:"title"`
+	Thumbnail        *MediaField        `json:"thumbnail,omitempty"`
+	Model            *SimpleCarModelRef `json:"model,omitempty"`
+	Price            int                `json:"price"`
+	MarketPrice      int                `json:"marketprice"`
+	CheapestShowroom *SimpleShowroom    `json:"cheapestshowroom,omitempty"` // Showroom with the lowest price, if any prices the variant
+}
+
+// ComparisonRow is a spec or feature across the compared variants
+type ComparisonRow struct {
+	Key     string    `json:"key,omitempty"` // Spec key; features have none
+	Label   string    `json:"label"`
+	Values  []*string `json:"values"`  // null where a variant lacks the spec or feature
+	Differs bool      `json:"differs"` // Whether any two values differ
+}
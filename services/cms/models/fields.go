@@ -3,29 +3,33 @@ package models
 // Common Strapi field types that can be reused across models
 
 // MediaField represents a simplified Strapi 5 media field with only essential data
-// Returns only: id (as documentId), width, height, url (prefixed), and formats
+// Returns: id (as documentId), width, height, url (rewritten), descriptive metadata, formats and placeholders
 type MediaField struct {
-	ID      string        `json:"id"`               // documentId from Strapi
-	Width   int           `json:"width,omitempty"`  // Original width
-	Height  int           `json:"height,omitempty"` // Original height
-	URL     string        `json:"url"`              // Prefixed URL
-	Formats *MediaFormats `json:"formats,omitempty"` // Available format sizes
+	ID              string       `json:"id"`                        // documentId from Strapi
+	Width           int          `json:"width,omitempty"`           // Original width
+	Height          int          `json:"height,omitempty"`          // Original height
+	URL             string       `json:"url"`                       // Rewritten URL
+	AlternativeText string       `json:"alternativeText,omitempty"` // Alt text for accessibility
+	Caption         string       `json:"caption,omitempty"`         // Caption entered in the media library
+	Mime            string       `json:"mime,omitempty"`            // MIME type, e.g. image/webp
+	Size            float64      `json:"size,omitempty"`            // Size in kilobytes
+	Formats         MediaFormats `json:"formats,omitempty"`         // Available format sizes
+	BlurHash        string       `json:"blurHash,omitempty"`        // BlurHash placeholder, once computed
+	DominantColor   string       `json:"dominantColor,omitempty"`   // Dominant color (#rrggbb), once computed
 }
 
 // MediaCollectionField represents a collection of media fields
 type MediaCollectionField []MediaField
 
-// MediaFormats contains different sizes of media files
-type MediaFormats struct {
-	Thumbnail *MediaFormat `json:"thumbnail,omitempty"`
-	Small     *MediaFormat `json:"small,omitempty"`
-	Medium    *MediaFormat `json:"medium,omitempty"`
-	Large     *MediaFormat `json:"large,omitempty"`
-}
+// MediaFormats contains the sizes of a media file keyed by breakpoint name
+// (Strapi's thumbnail, small, medium and large, plus any custom breakpoints)
+type MediaFormats map[string]*MediaFormat
 
 // MediaFormat represents a specific media format/size
 type MediaFormat struct {
-	Width  int    `json:"width"`
-	Height int    `json:"height"`
-	URL    string `json:"url"` // Prefixed URL
+	Width  int     `json:"width"`
+	Height int     `json:"height"`
+	URL    string  `json:"url"`            // Rewritten URL
+	Mime   string  `json:"mime,omitempty"` // MIME type
+	Size   float64 `json:"size,omitempty"` // Size in kilobytes
 }
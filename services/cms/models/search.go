@@ -0,0 +1,30 @@
+package models
+
+// Search result types
+const (
+	SearchTypeBrand    = "brand"
+	SearchTypeModel    = "model"
+	SearchTypeVariant  = "variant"
+	SearchTypeShowroom = "showroom"
+)
+
+// SearchResults is the response of the search endpoint, best matches first
+type SearchResults struct {
+	Query   string         `json:"query"`
+	Results []SearchResult `json:"results"`
+}
+
+// SearchResult is a brand, car model, car variant or showroom matching a search.
+// Autocomplete results only carry type, id, title and thumbnail.
+type SearchResult struct {
+	Type      string      `json:"type"` // brand, model, variant or showroom
+	ID        string      `json:"id"`
+	Title     string      `json:"title"`
+	Thumbnail *MediaField `json:"thumbnail,omitempty"`
+
+	Brand      *SimpleBrandRef    `json:"brand,omitempty"`      // Car models and variants
+	Model      *SimpleCarModelRef `json:"model,omitempty"`      // Variants
+	Year       int                `json:"year,omitempty"`       // Variants
+	Price      int                `json:"price,omitempty"`      // Variants
+	IsVerified bool               `json:"isVerified,omitempty"` // Showrooms
+}
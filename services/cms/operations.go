@@ -0,0 +1,153 @@
+package cms
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/vektah/gqlparser/v2"
+	"github.com/vektah/gqlparser/v2/ast"
+)
+
+// Operation is a pre-registered GraphQL operation
+type Operation struct {
+	Name  string // Operation name, e.g. GetBrands
+	Query string // Full GraphQL document
+	Hash  string // Hex SHA-256 of Query, used as its persisted query ID
+
+	Unverified bool // Skipped by ValidateOperations, see unverifiedOperations
+}
+
+// registeredQueries lists every GraphQL document the services send to Strapi
+var registeredQueries = []string{
+	GetBrandsQuery,
+	GetBrandByIDQuery,
+	GetCarModelsByBrandQuery,
+	GetVariantPricesByBrandQuery,
+	GetCarCatalogQuery,
+	GetCarVariantsByModelQuery,
+	GetCarModelByIDQuery,
+	GetAdvertisementsQuery,
+	GetAdvertisementByIDQuery,
+	GetCarVariantByIDQuery,
+	GetShowroomsQuery,
+	GetShowroomByIDQuery,
+	GetCarVariantsByShowroomQuery,
+	SearchQuery,
+	GetGovernoratesQuery,
+	GetAppVersionQuery,
+}
+
+// unverifiedOperations are registered operations whose schema.gql types are hand-written
+// rather than exported from Strapi. Validating them would only check the query against our
+// own guess, so ValidateOperations skips them until a real export covers their types.
+var unverifiedOperations = map[string]bool{
+	"AppVersion": true, // application-version single type, missing from the last export
+}
+
+// operations indexes the registered operations by query document
+var operations = func() map[string]*Operation {
+	byQuery := make(map[string]*Operation, len(registeredQueries))
+	for _, query := range registeredQueries {
+		hash := sha256.Sum256([]byte(query))
+		name := OperationName(query)
+		byQuery[query] = &Operation{
+			Name:       name,
+			Query:      query,
+			Hash:       hex.EncodeToString(hash[:]),
+			Unverified: unverifiedOperations[name],
+		}
+	}
+	return byQuery
+}()
+
+// Operations returns the registered operations sorted by name
+func Operations() []Operation {
+	ops := make([]Operation, 0, len(operations))
+	for _, op := range operations {
+		ops = append(ops, *op)
+	}
+	sort.Slice(ops, func(i, j int) bool { return ops[i].Name < ops[j].Name })
+	return ops
+}
+
+// lookupOperation returns the registered operation for a query document, or nil
+func lookupOperation(query string) *Operation {
+	return operations[query]
+}
+
+// ValidateOperations checks every registered operation against a GraphQL schema (SDL):
+// unknown types, fields and arguments, argument and variable types, and fields
+// selected twice under the same name. Unverified operations are skipped. All problems
+// are reported in one error.
+func ValidateOperations(schemaSDL string) error {
+	schema, gqlErr := gqlparser.LoadSchema(&ast.Source{Name: "schema.gql", Input: schemaSDL})
+	if gqlErr != nil {
+		return fmt.Errorf("failed to load GraphQL schema: %w", gqlErr)
+	}
+
+	var problems []string
+	for _, op := range Operations() {
+		if op.Name == "" {
+			problems = append(problems, fmt.Sprintf("unnamed operation: %s", strings.TrimSpace(op.Query)))
+			continue
+		}
+		if op.Unverified {
+			continue
+		}
+
+		doc, errs := gqlparser.LoadQuery(schema, op.Query)
+		for _, err := range errs {
+			problems = append(problems, fmt.Sprintf("%s: %s", op.Name, err.Message))
+		}
+		if doc == nil {
+			continue
+		}
+		for _, operation := range doc.Operations {
+			for _, duplicate := range duplicateFields(operation.SelectionSet, "") {
+				problems = append(problems, fmt.Sprintf("%s: field %q is selected more than once", op.Name, duplicate))
+			}
+		}
+	}
+
+	if len(problems) > 0 {
+		return errors.New("invalid GraphQL operations:\n  " + strings.Join(problems, "\n  "))
+	}
+	return nil
+}
+
+// duplicateFields returns the paths of fields selected more than once in the same selection set.
+// GraphQL merges them silently, but in a hand-written query they are almost always a typo.
+func duplicateFields(selections ast.SelectionSet, path string) []string {
+	var duplicates []string
+	seen := make(map[string]bool)
+	for _, selection := range selections {
+		field, ok := selection.(*ast.Field)
+		if !ok {
+			continue
+		}
+
+		fieldPath := field.Alias
+		if path != "" {
+			fieldPath = path + "." + field.Alias
+		}
+		if seen[field.Alias] {
+			duplicates = append(duplicates, fieldPath)
+		}
+		seen[field.Alias] = true
+
+		duplicates = append(duplicates, duplicateFields(field.SelectionSet, fieldPath)...)
+	}
+	return duplicates
+}
+
+// intValue returns a nullable Int of a generated result type, 0 when it is null
+func intValue(v *int) int {
+	if v == nil {
+		return 0
+	}
+	return *v
+}
@@ -0,0 +1,498 @@
+// Code generated by gqltypes from schema.gql and queries.go; DO NOT EDIT.
+
+package cms
+
+// appVersionApplicationVersion is the ApplicationVersion selected at applicationVersion
+type appVersionApplicationVersion struct {
+	MobileAppVersion     string `json:"MobileAppVersion"`
+	MobileAppBuildNumber *int   `json:"MobileAppBuildNumber"`
+	WebVersion           string `json:"WebVersion"`
+}
+
+// appVersionResult is the data of the AppVersion operation (GetAppVersionQuery)
+type appVersionResult struct {
+	ApplicationVersion *appVersionApplicationVersion `json:"applicationVersion"`
+}
+
+// getAdvertisementAdvertisement is the Advertisement selected at advertisement
+type getAdvertisementAdvertisement struct {
+	DocumentID string            `json:"documentId"`
+	Action     string            `json:"Action"`
+	Banner     *strapiMediaField `json:"Banner"`
+}
+
+// getAdvertisementResult is the data of the GetAdvertisement operation (GetAdvertisementByIDQuery)
+type getAdvertisementResult struct {
+	Advertisement *getAdvertisementAdvertisement `json:"advertisement"`
+}
+
+// getAdvertisementsAdvertisements is the Advertisement selected at advertisements
+type getAdvertisementsAdvertisements struct {
+	DocumentID string            `json:"documentId"`
+	Action     string            `json:"Action"`
+	Banner     *strapiMediaField `json:"Banner"`
+}
+
+// getAdvertisementsResult is the data of the GetAdvertisements operation (GetAdvertisementsQuery)
+type getAdvertisementsResult struct {
+	Advertisements []getAdvertisementsAdvertisements `json:"advertisements"`
+}
+
+// getBrandBrand is the Brand selected at brand
+type getBrandBrand struct {
+	DocumentID string            `json:"documentId"`
+	Name       string            `json:"Name"`
+	Slug       string            `json:"Slug"`
+	Logo       *strapiMediaField `json:"Logo"`
+}
+
+// getBrandResult is the data of the GetBrand operation (GetBrandByIDQuery)
+type getBrandResult struct {
+	Brand *getBrandBrand `json:"brand"`
+}
+
+// getBrandsBrands is the Brand selected at brands
+type getBrandsBrands struct {
+	DocumentID string            `json:"documentId"`
+	Name       string            `json:"Name"`
+	Logo       *strapiMediaField `json:"Logo"`
+}
+
+// getBrandsResult is the data of the GetBrands operation (GetBrandsQuery)
+type getBrandsResult struct {
+	Brands []getBrandsBrands `json:"brands"`
+}
+
+// getCarCatalogCarVariantsConnection is the CarVariantEntityResponseCollection selected at carVariants_connection
+type getCarCatalogCarVariantsConnection struct {
+	Nodes    []getCarCatalogCarVariantsConnectionNodes   `json:"nodes"`
+	PageInfo *getCarCatalogCarVariantsConnectionPageInfo `json:"pageInfo"`
+}
+
+// getCarCatalogCarVariantsConnectionNodes is the CarVariant selected at nodes
+type getCarCatalogCarVariantsConnectionNodes struct {
+	DocumentID          string                                                   `json:"documentId"`
+	Name                string                                                   `json:"Name"`
+	DisplayName         string                                                   `json:"DisplayName"`
+	Year                int                                                      `json:"Year"`
+	Price               int                                                      `json:"Price"`
+	MinimumDownPayment  *int                                                     `json:"MinimumDownPaymet"`
+	MinimumInstallments *int                                                     `json:"MinimumInstallments"`
+	ShowroomPricing     []getCarCatalogCarVariantsConnectionNodesShowroomPricing `json:"ShowroomPricing"`
+	CarModel            *getCarCatalogCarVariantsConnectionNodesCarModel         `json:"car_model"`
+}
+
+// getCarCatalogCarVariantsConnectionNodesCarModel is the CarModel selected at car_model
+type getCarCatalogCarVariantsConnectionNodesCarModel struct {
+	DocumentID string                                                `json:"documentId"`
+	Name       string                                                `json:"Name"`
+	BodyType   string                                                `json:"BodyType"`
+	FuelType   string                                                `json:"FuelType"`
+	Brand      *getCarCatalogCarVariantsConnectionNodesCarModelBrand `json:"brand"`
+	Images     []strapiMediaField                                    `json:"Images"`
+}
+
+// getCarCatalogCarVariantsConnectionNodesCarModelBrand is the Brand selected at brand
+type getCarCatalogCarVariantsConnectionNodesCarModelBrand struct {
+	DocumentID string `json:"documentId"`
+	Name       string `json:"Name"`
+	Slug       string `json:"Slug"`
+}
+
+// getCarCatalogCarVariantsConnectionNodesShowroomPricing is the ComponentRepeatablesCars selected at ShowroomPricing
+type getCarCatalogCarVariantsConnectionNodesShowroomPricing struct {
+	Price int `json:"Price"`
+}
+
+// getCarCatalogCarVariantsConnectionPageInfo is the Pagination selected at pageInfo
+type getCarCatalogCarVariantsConnectionPageInfo struct {
+	Page      int `json:"page"`
+	PageSize  int `json:"pageSize"`
+	PageCount int `json:"pageCount"`
+	Total     int `json:"total"`
+}
+
+// getCarCatalogResult is the data of the GetCarCatalog operation (GetCarCatalogQuery)
+type getCarCatalogResult struct {
+	CarVariantsConnection *getCarCatalogCarVariantsConnection `json:"carVariants_connection"`
+}
+
+// getCarModelCarModel is the CarModel selected at carModel
+type getCarModelCarModel struct {
+	DocumentID string                    `json:"documentId"`
+	Name       string                    `json:"Name"`
+	BodyType   string                    `json:"BodyType"`
+	FuelType   string                    `json:"FuelType"`
+	Slug       string                    `json:"Slug"`
+	Brand      *getCarModelCarModelBrand `json:"brand"`
+	Images     []strapiMediaField        `json:"Images"`
+}
+
+// getCarModelCarModelBrand is the Brand selected at brand
+type getCarModelCarModelBrand struct {
+	DocumentID string `json:"documentId"`
+	Slug       string `json:"Slug"`
+}
+
+// getCarModelResult is the data of the GetCarModel operation (GetCarModelByIDQuery)
+type getCarModelResult struct {
+	CarModel *getCarModelCarModel `json:"carModel"`
+}
+
+// getCarModelsByBrandCarModels is the CarModel selected at carModels
+type getCarModelsByBrandCarModels struct {
+	DocumentID string             `json:"documentId"`
+	Name       string             `json:"Name"`
+	Images     []strapiMediaField `json:"Images"`
+}
+
+// getCarModelsByBrandResult is the data of the GetCarModelsByBrand operation (GetCarModelsByBrandQuery)
+type getCarModelsByBrandResult struct {
+	CarModels []getCarModelsByBrandCarModels `json:"carModels"`
+}
+
+// getCarVariantCarVariant is the CarVariant selected at carVariant
+type getCarVariantCarVariant struct {
+	DocumentID          string                                   `json:"documentId"`
+	Name                string                                   `json:"Name"`
+	Price               int                                      `json:"Price"`
+	Year                int                                      `json:"Year"`
+	BrochureURL         string                                   `json:"BrochureURL"`
+	ReviewLink          string                                   `json:"ReviewLink"`
+	Warranty            string                                   `json:"Warranty"`
+	MinimumDownPayment  *int                                     `json:"MinimumDownPaymet"`
+	MinimumInstallments *int                                     `json:"MinimumInstallments"`
+	CarModel            *getCarVariantCarVariantCarModel         `json:"car_model"`
+	Specs               *getCarVariantCarVariantSpecs            `json:"Specs"`
+	Features            interface{}                              `json:"Features"`
+	ShowroomPricing     []getCarVariantCarVariantShowroomPricing `json:"ShowroomPricing"`
+}
+
+// getCarVariantCarVariantCarModel is the CarModel selected at car_model
+type getCarVariantCarVariantCarModel struct {
+	DocumentID string                                `json:"documentId"`
+	Name       string                                `json:"Name"`
+	BodyType   string                                `json:"BodyType"`
+	Brand      *getCarVariantCarVariantCarModelBrand `json:"brand"`
+	Images     []strapiMediaField                    `json:"Images"`
+}
+
+// getCarVariantCarVariantCarModelBrand is the Brand selected at brand
+type getCarVariantCarVariantCarModelBrand struct {
+	DocumentID string `json:"documentId"`
+	Slug       string `json:"Slug"`
+}
+
+// getCarVariantCarVariantShowroomPricing is the ComponentRepeatablesCars selected at ShowroomPricing
+type getCarVariantCarVariantShowroomPricing struct {
+	Price               int                                             `json:"Price"`
+	MinimumDownPayment  *int                                            `json:"MinimuDownpayment"`
+	MinimumInstallments *int                                            `json:"MinimumInstallements"`
+	Showroom            *getCarVariantCarVariantShowroomPricingShowroom `json:"showroom"`
+}
+
+// getCarVariantCarVariantShowroomPricingShowroom is the Showroom selected at showroom
+type getCarVariantCarVariantShowroomPricingShowroom struct {
+	DocumentID string            `json:"documentId"`
+	Name       string            `json:"Name"`
+	Logo       *strapiMediaField `json:"Logo"`
+}
+
+// getCarVariantCarVariantSpecs is the ComponentRepeatablesSpecs selected at Specs
+type getCarVariantCarVariantSpecs struct {
+	Motor               string   `json:"Motor"`
+	Transmission        string   `json:"Transmission"`
+	Acceleration        *float64 `json:"Acceleration"`
+	AssembledIn         string   `json:"AssembledIn"`
+	GroundClearanceInMM *int     `json:"GroundClearanceInMM"`
+	HeightInMM          *int     `json:"HeightInMM"`
+	Horsepower          int      `json:"Horsepower"`
+	LengthInMM          *int     `json:"LengthInMM"`
+	LiterPerKM          float64  `json:"LiterPerKM"`
+	MaxSpeed            int      `json:"MaxSpeed"`
+	Origin              string   `json:"Origin"`
+	Speed               int      `json:"Speed"`
+	TractionType        string   `json:"TractionType"`
+	TrunkSize           *int     `json:"TrunkSize"`
+	WheelBase           *int     `json:"WheelBase"`
+	WidthInMM           *int     `json:"WidthInMM"`
+	Seats               *int     `json:"Seats"`
+}
+
+// getCarVariantResult is the data of the GetCarVariant operation (GetCarVariantByIDQuery)
+type getCarVariantResult struct {
+	CarVariant *getCarVariantCarVariant `json:"carVariant"`
+}
+
+// getCarVariantsByShowroomCarVariants is the CarVariant selected at carVariants
+type getCarVariantsByShowroomCarVariants struct {
+	DocumentID      string                                               `json:"documentId"`
+	DisplayName     string                                               `json:"DisplayName"`
+	Images          []strapiMediaField                                   `json:"Images"`
+	ShowroomPricing []getCarVariantsByShowroomCarVariantsShowroomPricing `json:"ShowroomPricing"`
+}
+
+// getCarVariantsByShowroomCarVariantsShowroomPricing is the ComponentRepeatablesCars selected at ShowroomPricing
+type getCarVariantsByShowroomCarVariantsShowroomPricing struct {
+	Price               int  `json:"Price"`
+	MinimumDownPayment  *int `json:"MinimuDownpayment"`
+	MinimumInstallments *int `json:"MinimumInstallements"`
+}
+
+// getCarVariantsByShowroomResult is the data of the GetCarVariantsByShowroom operation (GetCarVariantsByShowroomQuery)
+type getCarVariantsByShowroomResult struct {
+	CarVariants []getCarVariantsByShowroomCarVariants `json:"carVariants"`
+}
+
+// getCarVariantsCarVariants is the CarVariant selected at carVariants
+type getCarVariantsCarVariants struct {
+	DocumentID          string                                     `json:"documentId"`
+	Name                string                                     `json:"Name"`
+	Price               int                                        `json:"Price"`
+	Year                int                                        `json:"Year"`
+	BrochureURL         string                                     `json:"BrochureURL"`
+	ReviewLink          string                                     `json:"ReviewLink"`
+	Warranty            string                                     `json:"Warranty"`
+	MinimumDownPayment  *int                                       `json:"MinimumDownPaymet"`
+	MinimumInstallments *int                                       `json:"MinimumInstallments"`
+	Specs               *getCarVariantsCarVariantsSpecs            `json:"Specs"`
+	ShowroomPricing     []getCarVariantsCarVariantsShowroomPricing `json:"ShowroomPricing"`
+}
+
+// getCarVariantsCarVariantsShowroomPricing is the ComponentRepeatablesCars selected at ShowroomPricing
+type getCarVariantsCarVariantsShowroomPricing struct {
+	Price    int                                               `json:"Price"`
+	Showroom *getCarVariantsCarVariantsShowroomPricingShowroom `json:"showroom"`
+}
+
+// getCarVariantsCarVariantsShowroomPricingShowroom is the Showroom selected at showroom
+type getCarVariantsCarVariantsShowroomPricingShowroom struct {
+	DocumentID string            `json:"documentId"`
+	Name       string            `json:"Name"`
+	Logo       *strapiMediaField `json:"Logo"`
+}
+
+// getCarVariantsCarVariantsSpecs is the ComponentRepeatablesSpecs selected at Specs
+type getCarVariantsCarVariantsSpecs struct {
+	Motor string `json:"Motor"`
+}
+
+// getCarVariantsResult is the data of the GetCarVariants operation (GetCarVariantsByModelQuery)
+type getCarVariantsResult struct {
+	CarVariants []getCarVariantsCarVariants `json:"carVariants"`
+}
+
+// getGovernatesGovernorates is the Governorate selected at governorates
+type getGovernatesGovernorates struct {
+	DocumentID string                            `json:"documentId"`
+	Name       string                            `json:"Name"`
+	Cities     []getGovernatesGovernoratesCities `json:"cities"`
+}
+
+// getGovernatesGovernoratesCities is the City selected at cities
+type getGovernatesGovernoratesCities struct {
+	DocumentID string `json:"documentId"`
+	Name       string `json:"Name"`
+}
+
+// getGovernatesResult is the data of the GetGovernates operation (GetGovernoratesQuery)
+type getGovernatesResult struct {
+	Governorates []getGovernatesGovernorates `json:"governorates"`
+}
+
+// getShowroomProfileResult is the data of the GetShowroomProfile operation (GetShowroomByIDQuery)
+type getShowroomProfileResult struct {
+	Showroom *getShowroomProfileShowroom `json:"showroom"`
+}
+
+// getShowroomProfileShowroom is the Showroom selected at showroom
+type getShowroomProfileShowroom struct {
+	DocumentID     string                                 `json:"documentId"`
+	Logo           *strapiMediaField                      `json:"Logo"`
+	Cover          *strapiMediaField                      `json:"Cover"`
+	Name           string                                 `json:"Name"`
+	Description    string                                 `json:"Description"`
+	IsVerified     bool                                   `json:"IsVerified"`
+	IsFeatured     bool                                   `json:"IsFeatured"`
+	OperatingHours string                                 `json:"OperatingHours"`
+	Location       *getShowroomProfileShowroomLocation    `json:"Location"`
+	ContactInfo    *getShowroomProfileShowroomContactInfo `json:"ContactInfo"`
+}
+
+// getShowroomProfileShowroomContactInfo is the ComponentCommonContactInfo selected at ContactInfo
+type getShowroomProfileShowroomContactInfo struct {
+	Email      string `json:"Email"`
+	Phone      string `json:"Phone"`
+	Facebook   string `json:"Facebook"`
+	Instagram  string `json:"Instagram"`
+	Tiktok     string `json:"Tiktok"`
+	Whatsapp   string `json:"Whatsapp"`
+	X          string `json:"X"`
+	Youtube    string `json:"Youtube"`
+	WebsiteURL string `json:"WebsiteURL"`
+}
+
+// getShowroomProfileShowroomLocation is the ComponentCommonLocation selected at Location
+type getShowroomProfileShowroomLocation struct {
+	Address     string                                         `json:"Address"`
+	Governorate *getShowroomProfileShowroomLocationGovernorate `json:"governorate"`
+	City        *getShowroomProfileShowroomLocationCity        `json:"city"`
+	Latitude    float64                                        `json:"Latitude"`
+	Longitude   float64                                        `json:"Longitude"`
+}
+
+// getShowroomProfileShowroomLocationCity is the City selected at city
+type getShowroomProfileShowroomLocationCity struct {
+	DocumentID string `json:"documentId"`
+	Name       string `json:"Name"`
+}
+
+// getShowroomProfileShowroomLocationGovernorate is the Governorate selected at governorate
+type getShowroomProfileShowroomLocationGovernorate struct {
+	DocumentID string `json:"documentId"`
+	Name       string `json:"Name"`
+}
+
+// getShowroomsResult is the data of the GetShowrooms operation (GetShowroomsQuery)
+type getShowroomsResult struct {
+	Showrooms []getShowroomsShowrooms `json:"showrooms"`
+}
+
+// getShowroomsShowrooms is the Showroom selected at showrooms
+type getShowroomsShowrooms struct {
+	DocumentID  string            `json:"documentId"`
+	Name        string            `json:"Name"`
+	Description string            `json:"Description"`
+	IsVerified  bool              `json:"IsVerified"`
+	IsFeatured  bool              `json:"IsFeatured"`
+	Logo        *strapiMediaField `json:"Logo"`
+}
+
+// getVariantPricesByBrandCarVariantsConnection is the CarVariantEntityResponseCollection selected at carVariants_connection
+type getVariantPricesByBrandCarVariantsConnection struct {
+	Nodes    []getVariantPricesByBrandCarVariantsConnectionNodes   `json:"nodes"`
+	PageInfo *getVariantPricesByBrandCarVariantsConnectionPageInfo `json:"pageInfo"`
+}
+
+// getVariantPricesByBrandCarVariantsConnectionNodes is the CarVariant selected at nodes
+type getVariantPricesByBrandCarVariantsConnectionNodes struct {
+	DocumentID      string                                                             `json:"documentId"`
+	Price           int                                                                `json:"Price"`
+	ShowroomPricing []getVariantPricesByBrandCarVariantsConnectionNodesShowroomPricing `json:"ShowroomPricing"`
+	CarModel        *getVariantPricesByBrandCarVariantsConnectionNodesCarModel         `json:"car_model"`
+}
+
+// getVariantPricesByBrandCarVariantsConnectionNodesCarModel is the CarModel selected at car_model
+type getVariantPricesByBrandCarVariantsConnectionNodesCarModel struct {
+	DocumentID string                                                          `json:"documentId"`
+	BodyType   string                                                          `json:"BodyType"`
+	Brand      *getVariantPricesByBrandCarVariantsConnectionNodesCarModelBrand `json:"brand"`
+}
+
+// getVariantPricesByBrandCarVariantsConnectionNodesCarModelBrand is the Brand selected at brand
+type getVariantPricesByBrandCarVariantsConnectionNodesCarModelBrand struct {
+	DocumentID string `json:"documentId"`
+	Slug       string `json:"Slug"`
+}
+
+// getVariantPricesByBrandCarVariantsConnectionNodesShowroomPricing is the ComponentRepeatablesCars selected at ShowroomPricing
+type getVariantPricesByBrandCarVariantsConnectionNodesShowroomPricing struct {
+	Price int `json:"Price"`
+}
+
+// getVariantPricesByBrandCarVariantsConnectionPageInfo is the Pagination selected at pageInfo
+type getVariantPricesByBrandCarVariantsConnectionPageInfo struct {
+	Page      int `json:"page"`
+	PageCount int `json:"pageCount"`
+}
+
+// getVariantPricesByBrandResult is the data of the GetVariantPricesByBrand operation (GetVariantPricesByBrandQuery)
+type getVariantPricesByBrandResult struct {
+	CarVariantsConnection *getVariantPricesByBrandCarVariantsConnection `json:"carVariants_connection"`
+}
+
+// searchBrands is the Brand selected at brands
+type searchBrands struct {
+	DocumentID    string                      `json:"documentId"`
+	Name          string                      `json:"Name"`
+	Logo          *strapiMediaField           `json:"Logo"`
+	Localizations []searchBrandsLocalizations `json:"localizations"`
+}
+
+// searchBrandsLocalizations is the Brand selected at localizations
+type searchBrandsLocalizations struct {
+	Name string `json:"Name"`
+}
+
+// searchCarModels is the CarModel selected at carModels
+type searchCarModels struct {
+	DocumentID    string                         `json:"documentId"`
+	Name          string                         `json:"Name"`
+	Brand         *searchCarModelsBrand          `json:"brand"`
+	Images        []strapiMediaField             `json:"Images"`
+	Localizations []searchCarModelsLocalizations `json:"localizations"`
+}
+
+// searchCarModelsBrand is the Brand selected at brand
+type searchCarModelsBrand struct {
+	DocumentID string `json:"documentId"`
+	Name       string `json:"Name"`
+}
+
+// searchCarModelsLocalizations is the CarModel selected at localizations
+type searchCarModelsLocalizations struct {
+	Name string `json:"Name"`
+}
+
+// searchCarVariants is the CarVariant selected at carVariants
+type searchCarVariants struct {
+	DocumentID    string                           `json:"documentId"`
+	Name          string                           `json:"Name"`
+	DisplayName   string                           `json:"DisplayName"`
+	Year          int                              `json:"Year"`
+	Price         int                              `json:"Price"`
+	CarModel      *searchCarVariantsCarModel       `json:"car_model"`
+	Localizations []searchCarVariantsLocalizations `json:"localizations"`
+}
+
+// searchCarVariantsCarModel is the CarModel selected at car_model
+type searchCarVariantsCarModel struct {
+	DocumentID string                          `json:"documentId"`
+	Name       string                          `json:"Name"`
+	Brand      *searchCarVariantsCarModelBrand `json:"brand"`
+	Images     []strapiMediaField              `json:"Images"`
+}
+
+// searchCarVariantsCarModelBrand is the Brand selected at brand
+type searchCarVariantsCarModelBrand struct {
+	DocumentID string `json:"documentId"`
+	Name       string `json:"Name"`
+}
+
+// searchCarVariantsLocalizations is the CarVariant selected at localizations
+type searchCarVariantsLocalizations struct {
+	Name        string `json:"Name"`
+	DisplayName string `json:"DisplayName"`
+}
+
+// searchResult is the data of the Search operation (SearchQuery)
+type searchResult struct {
+	Brands      []searchBrands      `json:"brands"`
+	CarModels   []searchCarModels   `json:"carModels"`
+	CarVariants []searchCarVariants `json:"carVariants"`
+	Showrooms   []searchShowrooms   `json:"showrooms"`
+}
+
+// searchShowrooms is the Showroom selected at showrooms
+type searchShowrooms struct {
+	DocumentID    string                         `json:"documentId"`
+	Name          string                         `json:"Name"`
+	IsVerified    bool                           `json:"IsVerified"`
+	Logo          *strapiMediaField              `json:"Logo"`
+	Localizations []searchShowroomsLocalizations `json:"localizations"`
+}
+
+// searchShowroomsLocalizations is the Showroom selected at localizations
+type searchShowroomsLocalizations struct {
+	Name string `json:"Name"`
+}
@@ -0,0 +1,45 @@
+package cms
+
+import (
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestValidateOperationsSkipsUnverified(t *testing.T) {
+	schemaBytes, err := os.ReadFile("../../schema.gql")
+	if err != nil {
+		t.Fatal(err)
+	}
+	schema := string(schemaBytes)
+
+	if err := ValidateOperations(schema); err != nil {
+		t.Fatalf("checked-in schema: %v", err)
+	}
+
+	// Without the hand-written field AppVersion cannot be valid, yet validation passes
+	withoutAppVersion := strings.Replace(schema, "  applicationVersion(status: PublicationStatus = PUBLISHED): ApplicationVersion\n", "", 1)
+	if withoutAppVersion == schema {
+		t.Fatal("applicationVersion field not found in schema.gql")
+	}
+	if err := ValidateOperations(withoutAppVersion); err != nil {
+		t.Errorf("schema without applicationVersion: %v", err)
+	}
+
+	// Verified operations are still checked
+	withoutBrands := strings.Replace(schema, "  brands(\n", "  brandz(\n", 1)
+	if err := ValidateOperations(withoutBrands); err == nil || !strings.Contains(err.Error(), "GetBrands") {
+		t.Errorf("schema without brands: got %v, want an error for GetBrands", err)
+	}
+}
+
+func TestOperationsMarkUnverified(t *testing.T) {
+	for _, op := range Operations() {
+		if op.Unverified != unverifiedOperations[op.Name] {
+			t.Errorf("%s: Unverified = %v", op.Name, op.Unverified)
+		}
+	}
+	if op := lookupOperation(GetAppVersionQuery); op == nil || !op.Unverified {
+		t.Errorf("AppVersion operation = %+v, want unverified", op)
+	}
+}
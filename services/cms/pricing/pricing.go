@@ -0,0 +1,160 @@
+// Package pricing derives the market prices of car variants from configurable rules
+package pricing
+
+import (
+	"api-gateway/services/cms/models"
+	"encoding/json"
+	"fmt"
+	"math"
+	"slices"
+	"strings"
+)
+
+// Method is how a rule derives a market price
+type Method string
+
+const (
+	MethodList           Method = "list"            // The variant's list price
+	MethodPercent        Method = "percent"         // List price plus Value percent
+	MethodAbsolute       Method = "absolute"        // List price plus Value
+	MethodShowroomMedian Method = "showroom-median" // Median of the showroom prices; skipped for variants without any
+)
+
+// Rule applies a method to the variants it matches. Empty criteria match every variant.
+type Rule struct {
+	Name      string   `json:"name,omitempty"`      // Reported in the derivation
+	Brands    []string `json:"brands,omitempty"`    // Brand documentIds or slugs
+	BodyTypes []string `json:"bodyTypes,omitempty"` // Car model body types, e.g. SUV
+	MinPrice  int      `json:"minPrice,omitempty"`  // Lower bound of the list price, inclusive
+	MaxPrice  int      `json:"maxPrice,omitempty"`  // Upper bound of the list price, inclusive; 0 means none
+	Method    Method   `json:"method"`
+	Value     float64  `json:"value,omitempty"` // Markup of percent and absolute rules
+}
+
+// Variant is what rules are evaluated against
+type Variant struct {
+	Price          int
+	BrandID        string
+	BrandSlug      string
+	BodyType       string
+	ShowroomPrices []int
+}
+
+// Rules is an ordered list of rules: the first matching rule that yields a price wins,
+// and variants no rule prices keep their list price
+type Rules []Rule
+
+// DefaultRules keep the gateway's original market price: the list price plus 20,000.
+// Other rules are opt-in through configuration.
+var DefaultRules = Rules{
+	{Method: MethodAbsolute, Value: 20000},
+}
+
+// ParseRules parses rules from JSON, e.g.
+// [{"brands": ["mercedes"], "method": "percent", "value": 12}, {"method": "showroom-median"}]
+func ParseRules(raw string) (Rules, error) {
+	var rules Rules
+	if err := json.Unmarshal([]byte(raw), &rules); err != nil {
+		return nil, fmt.Errorf("invalid pricing rules: %w", err)
+	}
+	for i, rule := range rules {
+		switch rule.Method {
+		case MethodList, MethodAbsolute, MethodShowroomMedian:
+		case MethodPercent:
+			if rule.Value <= -100 {
+				return nil, fmt.Errorf("invalid pricing rule %d: percent must be above -100", i+1)
+			}
+		default:
+			return nil, fmt.Errorf("invalid pricing rule %d: unknown method %q", i+1, rule.Method)
+		}
+		if rule.MaxPrice != 0 && rule.MaxPrice < rule.MinPrice {
+			return nil, fmt.Errorf("invalid pricing rule %d: maxPrice is below minPrice", i+1)
+		}
+	}
+	return rules, nil
+}
+
+// MarketPrice derives the market price of a variant and how it was derived
+func (r Rules) MarketPrice(variant Variant) (int, models.MarketPriceDerivation) {
+	for _, rule := range r {
+		if !rule.matches(variant) {
+			continue
+		}
+		if price, ok := rule.apply(variant); ok {
+			return price, models.MarketPriceDerivation{
+				Method: string(rule.Method),
+				Value:  rule.Value,
+				Rule:   rule.Name,
+			}
+		}
+	}
+	return variant.Price, models.MarketPriceDerivation{Method: string(MethodList)}
+}
+
+// Range derives the market price range of several variants and the distinct derivations used
+func (r Rules) Range(variants []Variant) (from, to int, derivations []models.MarketPriceDerivation) {
+	for i, variant := range variants {
+		price, derivation := r.MarketPrice(variant)
+		if i == 0 || price < from {
+			from = price
+		}
+		if i == 0 || price > to {
+			to = price
+		}
+		if !slices.Contains(derivations, derivation) {
+			derivations = append(derivations, derivation)
+		}
+	}
+	return from, to, derivations
+}
+
+// matches reports whether a rule's criteria select a variant
+func (rule Rule) matches(variant Variant) bool {
+	if len(rule.Brands) > 0 && !slices.ContainsFunc(rule.Brands, func(brand string) bool {
+		return brand == variant.BrandID || strings.EqualFold(brand, variant.BrandSlug)
+	}) {
+		return false
+	}
+	if len(rule.BodyTypes) > 0 && !slices.ContainsFunc(rule.BodyTypes, func(bodyType string) bool {
+		return strings.EqualFold(bodyType, variant.BodyType)
+	}) {
+		return false
+	}
+	if variant.Price < rule.MinPrice || (rule.MaxPrice != 0 && variant.Price > rule.MaxPrice) {
+		return false
+	}
+	return true
+}
+
+// apply derives a price with the rule's method; ok is false if the method has no data to work with
+func (rule Rule) apply(variant Variant) (price int, ok bool) {
+	switch rule.Method {
+	case MethodPercent:
+		return int(math.Round(float64(variant.Price) * (1 + rule.Value/100))), true
+	case MethodAbsolute:
+		return variant.Price + int(math.Round(rule.Value)), true
+	case MethodShowroomMedian:
+		return median(variant.ShowroomPrices)
+	default:
+		return variant.Price, true
+	}
+}
+
+// median returns the median of the positive prices
+func median(prices []int) (int, bool) {
+	var sorted []int
+	for _, price := range prices {
+		if price > 0 {
+			sorted = append(sorted, price)
+		}
+	}
+	if len(sorted) == 0 {
+		return 0, false
+	}
+	slices.Sort(sorted)
+	middle := len(sorted) / 2
+	if len(sorted)%2 == 0 {
+		return (sorted[middle-1] + sorted[middle]) / 2, true
+	}
+	return sorted[middle], true
+}
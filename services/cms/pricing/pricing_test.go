@@ -0,0 +1,146 @@
+package pricing
+
+import (
+	"api-gateway/services/cms/models"
+	"reflect"
+	"testing"
+)
+
+func TestMarketPrice(t *testing.T) {
+	variant := Variant{
+		Price:          1_000_000,
+		BrandID:        "brand-doc-id",
+		BrandSlug:      "mercedes",
+		BodyType:       "SUV",
+		ShowroomPrices: []int{1_100_000, 0, 1_050_000, 1_200_000},
+	}
+
+	tests := []struct {
+		name      string
+		rules     Rules
+		variant   Variant
+		wantPrice int
+		want      models.MarketPriceDerivation
+	}{
+		{"default rules add 20000", DefaultRules, variant, 1_020_000, models.MarketPriceDerivation{Method: "absolute", Value: 20000}},
+		{"no rules keep the list price", nil, variant, 1_000_000, models.MarketPriceDerivation{Method: "list"}},
+		{"percent", Rules{{Method: MethodPercent, Value: 12.5}}, variant, 1_125_000, models.MarketPriceDerivation{Method: "percent", Value: 12.5}},
+		{"percent rounds", Rules{{Method: MethodPercent, Value: 1}}, Variant{Price: 999}, 1009, models.MarketPriceDerivation{Method: "percent", Value: 1}},
+		{"negative absolute", Rules{{Method: MethodAbsolute, Value: -50_000}}, variant, 950_000, models.MarketPriceDerivation{Method: "absolute", Value: -50_000}},
+		{"showroom median ignores zero prices", Rules{{Method: MethodShowroomMedian}}, variant, 1_100_000, models.MarketPriceDerivation{Method: "showroom-median"}},
+		{"showroom median of an even count", Rules{{Method: MethodShowroomMedian}}, Variant{Price: 1, ShowroomPrices: []int{300, 100}}, 200, models.MarketPriceDerivation{Method: "showroom-median"}},
+		{
+			"showroom median without prices falls through",
+			Rules{{Method: MethodShowroomMedian}, {Method: MethodAbsolute, Value: 5}},
+			Variant{Price: 100},
+			105,
+			models.MarketPriceDerivation{Method: "absolute", Value: 5},
+		},
+		{
+			"brand matched by slug ignoring case",
+			Rules{{Name: "luxury", Brands: []string{"Mercedes"}, Method: MethodPercent, Value: 10}, {Method: MethodList}},
+			variant,
+			1_100_000,
+			models.MarketPriceDerivation{Method: "percent", Value: 10, Rule: "luxury"},
+		},
+		{
+			"brand matched by documentId",
+			Rules{{Brands: []string{"brand-doc-id"}, Method: MethodAbsolute, Value: 1}},
+			variant,
+			1_000_001,
+			models.MarketPriceDerivation{Method: "absolute", Value: 1},
+		},
+		{
+			"other brand skipped",
+			Rules{{Brands: []string{"bmw"}, Method: MethodPercent, Value: 10}, {Method: MethodAbsolute, Value: 1}},
+			variant,
+			1_000_001,
+			models.MarketPriceDerivation{Method: "absolute", Value: 1},
+		},
+		{
+			"body type ignoring case",
+			Rules{{BodyTypes: []string{"suv"}, Method: MethodAbsolute, Value: 7}},
+			variant,
+			1_000_007,
+			models.MarketPriceDerivation{Method: "absolute", Value: 7},
+		},
+		{
+			"price bounds are inclusive",
+			Rules{{MinPrice: 1_000_000, MaxPrice: 1_000_000, Method: MethodAbsolute, Value: 3}},
+			variant,
+			1_000_003,
+			models.MarketPriceDerivation{Method: "absolute", Value: 3},
+		},
+		{
+			"below the minimum price",
+			Rules{{MinPrice: 1_000_001, Method: MethodAbsolute, Value: 3}},
+			variant,
+			1_000_000,
+			models.MarketPriceDerivation{Method: "list"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			price, derivation := tt.rules.MarketPrice(tt.variant)
+			if price != tt.wantPrice {
+				t.Errorf("price = %d, want %d", price, tt.wantPrice)
+			}
+			if derivation != tt.want {
+				t.Errorf("derivation = %+v, want %+v", derivation, tt.want)
+			}
+		})
+	}
+}
+
+func TestRange(t *testing.T) {
+	rules := Rules{
+		{Name: "luxury", Brands: []string{"mercedes"}, Method: MethodPercent, Value: 10},
+		{Method: MethodAbsolute, Value: 20000},
+	}
+	variants := []Variant{
+		{Price: 500_000, BrandSlug: "kia"},
+		{Price: 400_000, BrandSlug: "mercedes"},
+		{Price: 900_000, BrandSlug: "kia"},
+	}
+
+	from, to, derivations := rules.Range(variants)
+	if from != 440_000 || to != 920_000 {
+		t.Errorf("range = %d-%d, want 440000-920000", from, to)
+	}
+	want := []models.MarketPriceDerivation{
+		{Method: "absolute", Value: 20000},
+		{Method: "percent", Value: 10, Rule: "luxury"},
+	}
+	if !reflect.DeepEqual(derivations, want) {
+		t.Errorf("derivations = %+v, want %+v", derivations, want)
+	}
+}
+
+func TestParseRules(t *testing.T) {
+	tests := []struct {
+		name    string
+		raw     string
+		want    Rules
+		wantErr bool
+	}{
+		{"valid", `[{"brands":["mercedes"],"method":"percent","value":12},{"method":"showroom-median"}]`, Rules{
+			{Brands: []string{"mercedes"}, Method: MethodPercent, Value: 12},
+			{Method: MethodShowroomMedian},
+		}, false},
+		{"invalid JSON", `{`, nil, true},
+		{"unknown method", `[{"method":"average"}]`, nil, true},
+		{"percent at -100", `[{"method":"percent","value":-100}]`, nil, true},
+		{"max below min", `[{"method":"list","minPrice":10,"maxPrice":5}]`, nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseRules(tt.raw)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseRules() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseRules() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -1,6 +1,9 @@
 package cms
 
-// GraphQL queries for all CMS operations
+// GraphQL queries for all CMS operations. Their typed results are generated into
+// operations_gen.go; run go generate after changing a query or schema.gql.
+
+//go:generate go run ./internal/gqltypes -schema ../../schema.gql -queries queries.go -out operations_gen.go
 
 const (
 	// GetBrandsQuery fetches all brands with simplified response
@@ -14,6 +17,11 @@ const (
 					url
 					width
 					height
+					alternativeText
+					caption
+					mime
+					size
+					updatedAt
 					formats
 				}
 			}
@@ -32,6 +40,11 @@ const (
 					url
 					width
 					height
+					alternativeText
+					caption
+					mime
+					size
+					updatedAt
 					formats
 				}
 			}
@@ -49,12 +62,102 @@ const (
 					url
 					width
 					height
+					alternativeText
+					caption
+					mime
+					size
+					updatedAt
 					formats
 				}
 			}
 		}
 	`
 
+	// GetVariantPricesByBrandQuery fetches one page of variant prices for every car model of a brand
+	GetVariantPricesByBrandQuery = `
+		query GetVariantPricesByBrand($brandDocumentId: ID!, $page: Int, $pageSize: Int, $locale: I18NLocaleCode) {
+			carVariants_connection(
+				filters: { car_model: { brand: { documentId: { eq: $brandDocumentId } } } }
+				pagination: { page: $page, pageSize: $pageSize }
+				locale: $locale
+			) {
+				nodes {
+					documentId
+					Price
+					ShowroomPricing {
+						Price
+					}
+					car_model {
+						documentId
+						BodyType
+						brand {
+							documentId
+							Slug
+						}
+					}
+				}
+				pageInfo {
+					page
+					pageCount
+				}
+			}
+		}
+	`
+
+	// GetCarCatalogQuery fetches one page of car variants matching catalog filters
+	GetCarCatalogQuery = `
+		query GetCarCatalog($filters: CarVariantFiltersInput, $sort: [String], $page: Int, $pageSize: Int, $locale: I18NLocaleCode) {
+			carVariants_connection(
+				filters: $filters
+				sort: $sort
+				pagination: { page: $page, pageSize: $pageSize }
+				locale: $locale
+			) {
+				nodes {
+					documentId
+					Name
+					DisplayName
+					Year
+					Price
+					MinimumDownPaymet
+					MinimumInstallments
+					ShowroomPricing {
+						Price
+					}
+					car_model {
+						documentId
+						Name
+						BodyType
+						FuelType
+						brand {
+							documentId
+							Name
+							Slug
+						}
+						Images(pagination: { limit: 1 }) {
+							documentId
+							url
+							width
+							height
+							alternativeText
+							caption
+							mime
+							size
+							updatedAt
+							formats
+						}
+					}
+				}
+				pageInfo {
+					page
+					pageSize
+					pageCount
+					total
+				}
+			}
+		}
+	`
+
 	// GetCarVariantsByModelQuery fetches variants with showrooms for a car model
 	GetCarVariantsByModelQuery = `
 		query GetCarVariants($carModelDocumentId: ID!, $locale: I18NLocaleCode) {
@@ -81,6 +184,11 @@ const (
 							url
 							width
 							height
+							alternativeText
+							caption
+							mime
+							size
+							updatedAt
 							formats
 						}
 					}
@@ -98,11 +206,20 @@ const (
 				BodyType
 				FuelType
 				Slug
+				brand {
+					documentId
+					Slug
+				}
 				Images {
 					documentId
 					url
 					width
 					height
+					alternativeText
+					caption
+					mime
+					size
+					updatedAt
 					formats
 				}
 			}
@@ -121,6 +238,11 @@ const (
 					name
 					width
 					height
+					alternativeText
+					caption
+					mime
+					size
+					updatedAt
 					formats
 				}
 			}
@@ -139,6 +261,11 @@ const (
 					name
 					width
 					height
+					alternativeText
+					caption
+					mime
+					size
+					updatedAt
 					formats
 				}
 			}
@@ -161,11 +288,21 @@ const (
 				car_model {
 					documentId
 					Name
+					BodyType
+					brand {
+						documentId
+						Slug
+					}
 					Images {
 						documentId
 						url
 						width
 						height
+						alternativeText
+						caption
+						mime
+						size
+						updatedAt
 						formats
 					}
 				}
@@ -183,7 +320,6 @@ const (
 					Origin
 					Speed
 					TractionType
-					Transmission
 					TrunkSize
 					WheelBase
 					WidthInMM
@@ -202,6 +338,11 @@ const (
 							url
 							width
 							height
+							alternativeText
+							caption
+							mime
+							size
+							updatedAt
 							formats
 						}
 					}
@@ -224,6 +365,11 @@ const (
 					url
 					width
 					height
+					alternativeText
+					caption
+					mime
+					size
+					updatedAt
 					formats
 				}
 			}
@@ -240,6 +386,11 @@ const (
 					url
 					width
 					height
+					alternativeText
+					caption
+					mime
+					size
+					updatedAt
 					formats
 				}
 				Cover {
@@ -247,6 +398,11 @@ const (
 					url
 					width
 					height
+					alternativeText
+					caption
+					mime
+					size
+					updatedAt
 					formats
 				}
 				Name
@@ -293,6 +449,11 @@ const (
 						url
 						width
 						height
+						alternativeText
+						caption
+						mime
+						size
+						updatedAt
 						formats
 				}
 				ShowroomPricing(filters: { showroom: { documentId: { eq: $showroomDocumentId } } }) {
@@ -303,6 +464,128 @@ const (
 			}
 		}`
 
+	// SearchQuery finds brands, car models, car variants and showrooms whose name contains $q,
+	// in the request locale or any of its localizations
+	SearchQuery = `
+		query Search($q: String!, $limit: Int, $locale: I18NLocaleCode) {
+			brands(
+				filters: { or: [{ Name: { containsi: $q } }, { Slug: { containsi: $q } }, { localizations: { Name: { containsi: $q } } }] }
+				pagination: { limit: $limit }
+				locale: $locale
+			) {
+				documentId
+				Name
+				Logo {
+					documentId
+					url
+					width
+					height
+					alternativeText
+					caption
+					mime
+					size
+					updatedAt
+					formats
+				}
+				localizations {
+					Name
+				}
+			}
+			carModels(
+				filters: { or: [{ Name: { containsi: $q } }, { localizations: { Name: { containsi: $q } } }] }
+				pagination: { limit: $limit }
+				locale: $locale
+			) {
+				documentId
+				Name
+				brand {
+					documentId
+					Name
+				}
+				Images(pagination: { limit: 1 }) {
+					documentId
+					url
+					width
+					height
+					alternativeText
+					caption
+					mime
+					size
+					updatedAt
+					formats
+				}
+				localizations {
+					Name
+				}
+			}
+			carVariants(
+				filters: {
+					or: [
+						{ Name: { containsi: $q } }
+						{ DisplayName: { containsi: $q } }
+						{ localizations: { or: [{ Name: { containsi: $q } }, { DisplayName: { containsi: $q } }] } }
+					]
+				}
+				pagination: { limit: $limit }
+				locale: $locale
+			) {
+				documentId
+				Name
+				DisplayName
+				Year
+				Price
+				car_model {
+					documentId
+					Name
+					brand {
+						documentId
+						Name
+					}
+					Images(pagination: { limit: 1 }) {
+						documentId
+						url
+						width
+						height
+						alternativeText
+						caption
+						mime
+						size
+						updatedAt
+						formats
+					}
+				}
+				localizations {
+					Name
+					DisplayName
+				}
+			}
+			showrooms(
+				filters: { or: [{ Name: { containsi: $q } }, { localizations: { Name: { containsi: $q } } }] }
+				pagination: { limit: $limit }
+				locale: $locale
+			) {
+				documentId
+				Name
+				IsVerified
+				Logo {
+					documentId
+					url
+					width
+					height
+					alternativeText
+					caption
+					mime
+					size
+					updatedAt
+					formats
+				}
+				localizations {
+					Name
+				}
+			}
+		}
+	`
+
 	// GetGovernoratesQuery fetches all governorates with their cities
 	GetGovernoratesQuery = `
 		query GetGovernates($locale: I18NLocaleCode) {
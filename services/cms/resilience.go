@@ -0,0 +1,162 @@
+package cms
+
+import (
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"sync"
+	"time"
+)
+
+// Circuit breaker states reported in BreakerStatus
+const (
+	BreakerClosed   = "closed"    // Requests flow to the CMS
+	BreakerOpen     = "open"      // Requests fail fast without reaching the CMS
+	BreakerHalfOpen = "half-open" // A single trial request decides whether to close again
+)
+
+// RetryConfig holds the retry settings for transient CMS failures
+type RetryConfig struct {
+	MaxRetries int           // Retries after the first attempt (default 2; negative disables retries)
+	BaseDelay  time.Duration // Backoff before the first retry, doubled for each further retry (default 100ms)
+	MaxDelay   time.Duration // Upper bound of the backoff (default 2s)
+}
+
+// BreakerConfig holds the circuit breaker settings for CMS requests
+type BreakerConfig struct {
+	FailureThreshold int           // Consecutive failed attempts that open the circuit (default 5)
+	OpenDuration     time.Duration // How long the circuit stays open before a trial request (default 30s)
+}
+
+// BreakerStatus is a snapshot of the circuit breaker for health output
+type BreakerStatus struct {
+	State               string    `json:"state"`
+	Since               time.Time `json:"since"`
+	ConsecutiveFailures int       `json:"consecutiveFailures"`
+	LastError           string    `json:"lastError,omitempty"`
+}
+
+// withDefaults fills in zero values
+func (r RetryConfig) withDefaults() RetryConfig {
+	if r.MaxRetries == 0 {
+		r.MaxRetries = 2
+	}
+	if r.MaxRetries < 0 {
+		r.MaxRetries = 0
+	}
+	if r.BaseDelay == 0 {
+		r.BaseDelay = 100 * time.Millisecond
+	}
+	if r.MaxDelay == 0 {
+		r.MaxDelay = 2 * time.Second
+	}
+	return r
+}
+
+// backoff returns the delay before retry number attempt+1: exponential, capped,
+// with the upper half jittered so that replicas do not retry in lockstep
+func (r RetryConfig) backoff(attempt int) time.Duration {
+	delay := r.MaxDelay
+	if attempt < 30 && r.BaseDelay<<attempt < r.MaxDelay {
+		delay = r.BaseDelay << attempt
+	}
+	return delay/2 + rand.N(delay/2+1)
+}
+
+// isRetryable reports whether err is a transient failure worth retrying: connection
+// errors and 502/503/504 responses. Timeouts are not retried as they already took
+// the full request timeout.
+func isRetryable(err error) bool {
+	return errors.Is(err, ErrUnavailable)
+}
+
+// isUpstreamFailure reports whether err says the CMS is unhealthy, as opposed to
+// the CMS answering with an error
+func isUpstreamFailure(err error) bool {
+	return errors.Is(err, ErrUnavailable) || errors.Is(err, ErrTimeout)
+}
+
+// circuitBreaker stops sending requests to the CMS after repeated failures
+type circuitBreaker struct {
+	config BreakerConfig
+
+	mu     sync.Mutex
+	status BreakerStatus
+	trial  bool // A half-open trial request is in flight
+}
+
+// newCircuitBreaker creates a closed breaker
+func newCircuitBreaker(config BreakerConfig) *circuitBreaker {
+	if config.FailureThreshold == 0 {
+		config.FailureThreshold = 5
+	}
+	if config.OpenDuration == 0 {
+		config.OpenDuration = 30 * time.Second
+	}
+
+	return &circuitBreaker{
+		config: config,
+		status: BreakerStatus{State: BreakerClosed, Since: time.Now()},
+	}
+}
+
+// Status returns the current state for health output
+func (b *circuitBreaker) Status() BreakerStatus {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.status
+}
+
+// allow reports whether a request may be sent. Once the open duration has passed,
+// a single trial request is let through.
+func (b *circuitBreaker) allow() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.status.State {
+	case BreakerOpen:
+		if time.Since(b.status.Since) < b.config.OpenDuration {
+			return false
+		}
+		b.status.State = BreakerHalfOpen
+		b.status.Since = time.Now()
+		b.trial = true
+		return true
+	case BreakerHalfOpen:
+		if b.trial {
+			return false
+		}
+		b.trial = true
+		return true
+	default:
+		return true
+	}
+}
+
+// record observes the outcome of an allowed request
+func (b *circuitBreaker) record(err error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.status.State == BreakerHalfOpen {
+		b.trial = false
+	}
+
+	if !isUpstreamFailure(err) {
+		if b.status.State != BreakerClosed {
+			b.status = BreakerStatus{State: BreakerClosed, Since: time.Now()}
+			fmt.Printf("[GraphQL Client] Circuit closed, CMS is reachable again\n")
+		}
+		b.status.ConsecutiveFailures = 0
+		return
+	}
+
+	b.status.ConsecutiveFailures++
+	b.status.LastError = err.Error()
+	if b.status.State == BreakerHalfOpen ||
+		(b.status.State == BreakerClosed && b.status.ConsecutiveFailures >= b.config.FailureThreshold) {
+		b.status.State = BreakerOpen
+		b.status.Since = time.Now()
+		fmt.Printf("[GraphQL Client] Circuit open after %d consecutive failures, last: %v\n", b.status.ConsecutiveFailures, err)
+	}
+}
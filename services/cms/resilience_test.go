@@ -0,0 +1,201 @@
+package cms
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestCircuitBreakerTransitions(t *testing.T) {
+	unavailable := &Error{Kind: ErrUnavailable, Message: "CMS responded with status 503"}
+	b := newCircuitBreaker(BreakerConfig{FailureThreshold: 3, OpenDuration: time.Minute})
+
+	// Client errors and successes keep it closed and reset the count
+	for _, err := range []error{unavailable, unavailable, newNotFoundError("brand"), nil, unavailable, unavailable} {
+		if !b.allow() {
+			t.Fatal("closed breaker rejected a request")
+		}
+		b.record(err)
+	}
+	if status := b.Status(); status.State != BreakerClosed || status.ConsecutiveFailures != 2 {
+		t.Fatalf("status = %+v, want closed with 2 failures", status)
+	}
+
+	// Timeouts count as failures too; the third consecutive one opens it
+	b.allow()
+	b.record(&Error{Kind: ErrTimeout})
+	if status := b.Status(); status.State != BreakerOpen || status.LastError != "CMS request timed out" {
+		t.Fatalf("status = %+v, want open", status)
+	}
+	if b.allow() {
+		t.Fatal("open breaker allowed a request")
+	}
+
+	// After the open duration a single trial is let through
+	b.status.Since = time.Now().Add(-time.Minute)
+	if !b.allow() {
+		t.Fatal("breaker did not allow a trial after the open duration")
+	}
+	if state := b.Status().State; state != BreakerHalfOpen {
+		t.Fatalf("state = %s, want half-open", state)
+	}
+	if b.allow() {
+		t.Fatal("half-open breaker allowed a second request during the trial")
+	}
+
+	// A failed trial opens it again for another open duration
+	b.record(unavailable)
+	if state := b.Status().State; state != BreakerOpen || b.allow() {
+		t.Fatalf("state = %s after a failed trial, want open and rejecting", state)
+	}
+
+	// A successful trial closes it
+	b.status.Since = time.Now().Add(-time.Minute)
+	b.allow()
+	b.record(nil)
+	if status := b.Status(); status.State != BreakerClosed || status.ConsecutiveFailures != 0 {
+		t.Fatalf("status = %+v after a successful trial, want closed", status)
+	}
+	if !b.allow() {
+		t.Fatal("closed breaker rejected a request")
+	}
+}
+
+func TestRetryConfigBackoff(t *testing.T) {
+	config := RetryConfig{BaseDelay: 100 * time.Millisecond, MaxDelay: time.Second}.withDefaults()
+
+	tests := []struct {
+		attempt  int
+		min, max time.Duration
+	}{
+		{0, 50 * time.Millisecond, 100 * time.Millisecond},
+		{1, 100 * time.Millisecond, 200 * time.Millisecond},
+		{3, 400 * time.Millisecond, 800 * time.Millisecond},
+		{4, 500 * time.Millisecond, time.Second},
+		{40, 500 * time.Millisecond, time.Second},
+	}
+
+	for _, test := range tests {
+		for range 50 {
+			if delay := config.backoff(test.attempt); delay < test.min || delay > test.max {
+				t.Fatalf("backoff(%d) = %s, want between %s and %s", test.attempt, delay, test.min, test.max)
+			}
+		}
+	}
+	if retries := (RetryConfig{MaxRetries: -1}).withDefaults().MaxRetries; retries != 0 {
+		t.Errorf("negative MaxRetries gives %d retries, want 0", retries)
+	}
+}
+
+func TestFetchGraphQLRetriesOnlyUnavailable(t *testing.T) {
+	tests := []struct {
+		name         string
+		responses    []func(w http.ResponseWriter) // Per attempt; the last one repeats
+		wantErr      error
+		wantRequests int64
+	}{
+		{
+			"503 then success",
+			[]func(w http.ResponseWriter){respondStatus(http.StatusServiceUnavailable), respondStatus(http.StatusBadGateway), respondBody(`{"data":{}}`)},
+			nil,
+			3,
+		},
+		{
+			"unavailable until retries run out",
+			[]func(w http.ResponseWriter){respondStatus(http.StatusServiceUnavailable)},
+			ErrUnavailable,
+			3,
+		},
+		{
+			"GraphQL errors are not retried",
+			[]func(w http.ResponseWriter){respondBody(`{"errors":[{"message":"boom","extensions":{"code":"INTERNAL_SERVER_ERROR"}}]}`)},
+			ErrUpstream,
+			1,
+		},
+		{
+			"not found is not retried",
+			[]func(w http.ResponseWriter){respondBody(`{"errors":[{"message":"missing","extensions":{"code":"NOT_FOUND"}}]}`)},
+			ErrNotFound,
+			1,
+		},
+		{
+			"500 is not retried",
+			[]func(w http.ResponseWriter){respondStatus(http.StatusInternalServerError)},
+			ErrUpstream,
+			1,
+		},
+		{
+			"timeouts are not retried",
+			[]func(w http.ResponseWriter){func(w http.ResponseWriter) { time.Sleep(200 * time.Millisecond) }},
+			ErrTimeout,
+			1,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var attempt atomic.Int64
+			server, requests := newStubCMS(t, func(w http.ResponseWriter, r *http.Request) {
+				i := min(int(attempt.Add(1)), len(test.responses)) - 1
+				test.responses[i](w)
+			})
+			client := NewCMSClient(Config{
+				BaseURL:                 server.URL,
+				RequestTimeout:          50 * time.Millisecond,
+				Retry:                   RetryConfig{MaxRetries: 2, BaseDelay: time.Millisecond},
+				DisablePersistedQueries: true,
+			})
+
+			_, err := client.ExecuteGraphQL(context.Background(), `query { brands { documentId } }`, nil)
+			if test.wantErr == nil && err != nil || test.wantErr != nil && !errors.Is(err, test.wantErr) {
+				t.Errorf("error = %v, want %v", err, test.wantErr)
+			}
+			if got := requests.Load(); got != test.wantRequests {
+				t.Errorf("upstream requests = %d, want %d", got, test.wantRequests)
+			}
+			if retries := client.Stats().Retries; retries != uint64(test.wantRequests-1) {
+				t.Errorf("retries = %d, want %d", retries, test.wantRequests-1)
+			}
+		})
+	}
+}
+
+func TestFetchGraphQLFailsFastWhileBreakerOpen(t *testing.T) {
+	server, requests := newStubCMS(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	})
+	client := NewCMSClient(Config{
+		BaseURL:                 server.URL,
+		Retry:                   RetryConfig{MaxRetries: -1},
+		Breaker:                 BreakerConfig{FailureThreshold: 2, OpenDuration: time.Minute},
+		DisablePersistedQueries: true,
+	})
+
+	for range 2 {
+		client.ExecuteGraphQL(context.Background(), `query { brands { documentId } }`, nil)
+	}
+	_, err := client.ExecuteGraphQL(context.Background(), `query { brands { documentId } }`, nil)
+	if !errors.Is(err, ErrUnavailable) {
+		t.Errorf("error = %v, want ErrUnavailable", err)
+	}
+	if got := requests.Load(); got != 2 {
+		t.Errorf("upstream requests = %d, want 2", got)
+	}
+	if stats := client.Stats(); stats.BreakerRejections != 1 {
+		t.Errorf("breaker rejections = %d, want 1", stats.BreakerRejections)
+	}
+	if state := client.BreakerStatus().State; state != BreakerOpen {
+		t.Errorf("state = %s, want open", state)
+	}
+}
+
+func respondStatus(code int) func(w http.ResponseWriter) {
+	return func(w http.ResponseWriter) { w.WriteHeader(code) }
+}
+
+func respondBody(payload string) func(w http.ResponseWriter) {
+	return func(w http.ResponseWriter) { w.Write([]byte(payload)) }
+}
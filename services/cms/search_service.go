@@ -0,0 +1,304 @@
+package cms
+
+import (
+	"api-gateway/services/cms/models"
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Search limits
+const (
+	searchMinQueryLength    = 2
+	searchMaxQueryLength    = 100
+	searchDefaultLimit      = 20
+	searchAutocompleteLimit = 8
+	searchMaxLimit          = 50
+
+	// searchAutocompleteTTL caps how long autocomplete results are cached
+	searchAutocompleteTTL = 2 * time.Minute
+)
+
+// searchTypeOrder ranks result types against each other for equally good matches
+var searchTypeOrder = map[string]int{
+	models.SearchTypeBrand:    0,
+	models.SearchTypeModel:    1,
+	models.SearchTypeVariant:  2,
+	models.SearchTypeShowroom: 3,
+}
+
+// Match qualities, best first
+const (
+	matchExact = iota
+	matchPrefix
+	matchWordPrefix
+	matchSubstring
+	matchNone // Matched by Strapi on a field not selected for ranking
+)
+
+// SearchRequest is a parsed search request
+type SearchRequest struct {
+	Text         string
+	Limit        int  // Results returned in total
+	Autocomplete bool // Only return ids, titles and thumbnails
+}
+
+// ParseSearchRequest parses the query parameters of the search endpoint: q, limit and autocomplete
+func ParseSearchRequest(params map[string]string) (SearchRequest, error) {
+	request := SearchRequest{
+		Text:  strings.Join(strings.Fields(params["q"]), " "),
+		Limit: searchDefaultLimit,
+	}
+
+	if length := utf8.RuneCountInString(request.Text); length < searchMinQueryLength {
+		return SearchRequest{}, newBadInputError(fmt.Sprintf("q must be at least %d characters", searchMinQueryLength))
+	} else if length > searchMaxQueryLength {
+		return SearchRequest{}, newBadInputError(fmt.Sprintf("q must be at most %d characters", searchMaxQueryLength))
+	}
+
+	if raw := params["autocomplete"]; raw != "" {
+		autocomplete, err := strconv.ParseBool(raw)
+		if err != nil {
+			return SearchRequest{}, newBadInputError("autocomplete must be true or false")
+		}
+		request.Autocomplete = autocomplete
+		if autocomplete {
+			request.Limit = searchAutocompleteLimit
+		}
+	}
+
+	if raw := params["limit"]; raw != "" {
+		limit, err := strconv.Atoi(raw)
+		if err != nil || limit < 1 || limit > searchMaxLimit {
+			return SearchRequest{}, newBadInputError(fmt.Sprintf("limit must be an integer between 1 and %d", searchMaxLimit))
+		}
+		request.Limit = limit
+	}
+	return request, nil
+}
+
+// SearchServiceGraphQL searches brands, car models, car variants and showrooms using GraphQL
+type SearchServiceGraphQL struct {
+	client *CMSClient
+}
+
+// NewSearchServiceGraphQL creates a new GraphQL-based search service
+func NewSearchServiceGraphQL(client *CMSClient) *SearchServiceGraphQL {
+	return &SearchServiceGraphQL{
+		client: client,
+	}
+}
+
+// searchCandidate is a result with the names it is ranked by: its title, then the
+// names of its other localizations
+type searchCandidate struct {
+	result  models.SearchResult
+	names   []string
+	quality int
+}
+
+// Search finds brands, car models, car variants and showrooms whose name contains the
+// query text in any locale, ranked by match quality (exact, prefix, word prefix, substring),
+// then type, then title length. Titles are in the request locale.
+func (s *SearchServiceGraphQL) Search(ctx context.Context, request SearchRequest) (*models.SearchResults, error) {
+	variables := map[string]interface{}{
+		"q":     request.Text,
+		"limit": request.Limit,
+	}
+
+	// Autocomplete sends a search per keystroke: keep those entries briefly and never serve them stale
+	policy := s.client.CachePolicyFor(SearchQuery)
+	if request.Autocomplete {
+		policy.TTL = min(policy.TTL, searchAutocompleteTTL)
+		policy.StaleTTL = 0
+	}
+
+	data, err := s.client.ExecuteGraphQLWithPolicy(ctx, SearchQuery, variables, policy)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search: %w", err)
+	}
+
+	var result searchResult
+	if err := json.Unmarshal(data, &result); err != nil {
+		return nil, newDecodeError("search results", err)
+	}
+
+	var candidates []searchCandidate
+	for _, brand := range result.Brands {
+		candidate := searchCandidate{
+			result: models.SearchResult{
+				Type:      models.SearchTypeBrand,
+				ID:        brand.DocumentID,
+				Title:     brand.Name,
+				Thumbnail: s.client.media.Field(brand.Logo),
+			},
+			names: []string{brand.Name},
+		}
+		for _, localization := range brand.Localizations {
+			candidate.names = append(candidate.names, localization.Name)
+		}
+		candidates = append(candidates, candidate)
+	}
+
+	for _, model := range result.CarModels {
+		candidate := searchCandidate{
+			result: models.SearchResult{
+				Type:  models.SearchTypeModel,
+				ID:    model.DocumentID,
+				Title: model.Name,
+			},
+			names: []string{model.Name},
+		}
+		if len(model.Images) > 0 {
+			candidate.result.Thumbnail = s.client.media.Field(&model.Images[0])
+		}
+		if model.Brand != nil {
+			candidate.result.Brand = &models.SimpleBrandRef{ID: model.Brand.DocumentID, Title: model.Brand.Name}
+		}
+		for _, localization := range model.Localizations {
+			candidate.names = append(candidate.names, localization.Name)
+		}
+		candidates = append(candidates, candidate)
+	}
+
+	for _, variant := range result.CarVariants {
+		candidate := searchCandidate{
+			result: models.SearchResult{
+				Type:  models.SearchTypeVariant,
+				ID:    variant.DocumentID,
+				Title: variant.DisplayName,
+				Year:  variant.Year,
+				Price: variant.Price,
+			},
+			names: []string{variant.DisplayName, variant.Name},
+		}
+		if candidate.result.Title == "" {
+			candidate.result.Title = variant.Name
+		}
+		if model := variant.CarModel; model != nil {
+			candidate.result.Model = &models.SimpleCarModelRef{ID: model.DocumentID, Title: model.Name}
+			if model.Brand != nil {
+				candidate.result.Brand = &models.SimpleBrandRef{ID: model.Brand.DocumentID, Title: model.Brand.Name}
+			}
+			if len(model.Images) > 0 {
+				candidate.result.Thumbnail = s.client.media.Field(&model.Images[0])
+			}
+		}
+		for _, localization := range variant.Localizations {
+			candidate.names = append(candidate.names, localization.DisplayName, localization.Name)
+		}
+		candidates = append(candidates, candidate)
+	}
+
+	for _, showroom := range result.Showrooms {
+		candidate := searchCandidate{
+			result: models.SearchResult{
+				Type:       models.SearchTypeShowroom,
+				ID:         showroom.DocumentID,
+				Title:      showroom.Name,
+				Thumbnail:  s.client.media.Field(showroom.Logo),
+				IsVerified: showroom.IsVerified,
+			},
+			names: []string{showroom.Name},
+		}
+		for _, localization := range showroom.Localizations {
+			candidate.names = append(candidate.names, localization.Name)
+		}
+		candidates = append(candidates, candidate)
+	}
+
+	rankSearchCandidates(normalizeSearchText(request.Text), candidates)
+
+	results := &models.SearchResults{
+		Query:   request.Text,
+		Results: make([]models.SearchResult, 0, min(len(candidates), request.Limit)),
+	}
+	for _, candidate := range candidates[:min(len(candidates), request.Limit)] {
+		if request.Autocomplete {
+			candidate.result = models.SearchResult{
+				Type:      candidate.result.Type,
+				ID:        candidate.result.ID,
+				Title:     candidate.result.Title,
+				Thumbnail: candidate.result.Thumbnail,
+			}
+		}
+		results.Results = append(results.Results, candidate.result)
+	}
+
+	return results, nil
+}
+
+// rankSearchCandidates sorts candidates by their best match of the normalized text,
+// then type, then title length and title
+func rankSearchCandidates(text string, candidates []searchCandidate) {
+	for i := range candidates {
+		candidates[i].quality = matchQuality(text, candidates[i].names)
+	}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		a, b := candidates[i], candidates[j]
+		if a.quality != b.quality {
+			return a.quality < b.quality
+		}
+		if a.result.Type != b.result.Type {
+			return searchTypeOrder[a.result.Type] < searchTypeOrder[b.result.Type]
+		}
+		if lengthA, lengthB := utf8.RuneCountInString(a.result.Title), utf8.RuneCountInString(b.result.Title); lengthA != lengthB {
+			return lengthA < lengthB
+		}
+		return a.result.Title < b.result.Title
+	})
+}
+
+// matchQuality returns the best match of the normalized text in any of the names
+func matchQuality(text string, names []string) int {
+	best := matchNone
+	for _, name := range names {
+		name = normalizeSearchText(name)
+		if name == "" {
+			continue
+		}
+		if name == text {
+			return matchExact
+		}
+		for offset := 0; ; {
+			index := strings.Index(name[offset:], text)
+			if index < 0 {
+				break
+			}
+			index += offset
+			switch {
+			case index == 0:
+				best = min(best, matchPrefix)
+			case !isWordRune(lastRune(name[:index])):
+				best = min(best, matchWordPrefix)
+			default:
+				best = min(best, matchSubstring)
+			}
+			_, size := utf8.DecodeRuneInString(name[index:])
+			offset = index + size
+		}
+	}
+	return best
+}
+
+// normalizeSearchText lower-cases text and collapses whitespace
+func normalizeSearchText(text string) string {
+	return strings.Join(strings.Fields(strings.ToLower(text)), " ")
+}
+
+// lastRune returns the last rune of s
+func lastRune(s string) rune {
+	r, _ := utf8.DecodeLastRuneInString(s)
+	return r
+}
+
+// isWordRune reports whether r is part of a word
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r)
+}
@@ -0,0 +1,161 @@
+package cms
+
+import (
+	"api-gateway/services/cms/models"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseSearchRequest(t *testing.T) {
+	tests := []struct {
+		name    string
+		params  map[string]string
+		want    SearchRequest
+		wantErr bool
+	}{
+		{"defaults", map[string]string{"q": "bmw"}, SearchRequest{Text: "bmw", Limit: searchDefaultLimit}, false},
+		{"collapses whitespace", map[string]string{"q": "  bmw \t x5 "}, SearchRequest{Text: "bmw x5", Limit: searchDefaultLimit}, false},
+		{"autocomplete limit", map[string]string{"q": "bm", "autocomplete": "true"}, SearchRequest{Text: "bm", Limit: searchAutocompleteLimit, Autocomplete: true}, false},
+		{"explicit limit wins", map[string]string{"q": "bm", "autocomplete": "1", "limit": "3"}, SearchRequest{Text: "bm", Limit: 3, Autocomplete: true}, false},
+		{"autocomplete off", map[string]string{"q": "bm", "autocomplete": "false"}, SearchRequest{Text: "bm", Limit: searchDefaultLimit}, false},
+		{"counts runes", map[string]string{"q": "بي"}, SearchRequest{Text: "بي", Limit: searchDefaultLimit}, false},
+		{"too short", map[string]string{"q": " b "}, SearchRequest{}, true},
+		{"missing", map[string]string{}, SearchRequest{}, true},
+		{"too long", map[string]string{"q": strings.Repeat("a", searchMaxQueryLength+1)}, SearchRequest{}, true},
+		{"bad autocomplete", map[string]string{"q": "bmw", "autocomplete": "yes"}, SearchRequest{}, true},
+		{"zero limit", map[string]string{"q": "bmw", "limit": "0"}, SearchRequest{}, true},
+		{"limit above max", map[string]string{"q": "bmw", "limit": "51"}, SearchRequest{}, true},
+		{"non-numeric limit", map[string]string{"q": "bmw", "limit": "ten"}, SearchRequest{}, true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := ParseSearchRequest(test.params)
+			if test.wantErr {
+				if !errors.Is(err, ErrBadInput) {
+					t.Fatalf("error = %v, want ErrBadInput", err)
+				}
+				return
+			}
+			if err != nil || got != test.want {
+				t.Errorf("ParseSearchRequest() = %+v, %v; want %+v", got, err, test.want)
+			}
+		})
+	}
+}
+
+func TestMatchQuality(t *testing.T) {
+	tests := []struct {
+		text  string
+		names []string
+		want  int
+	}{
+		{"bmw", []string{"BMW"}, matchExact},
+		{"bmw x5", []string{"  BMW   X5 "}, matchExact},
+		{"bmw", []string{"BMW X5"}, matchPrefix},
+		{"x5", []string{"BMW X5"}, matchWordPrefix},
+		{"class", []string{"Mercedes-Benz C-Class"}, matchWordPrefix},
+		{"mw", []string{"BMW"}, matchSubstring},
+		{"an", []string{"Sedan Anniversary"}, matchWordPrefix},
+		{"tesla", []string{"BMW"}, matchNone},
+		{"tesla", nil, matchNone},
+		{"bmw", []string{"", "BMW Motors", "bmw"}, matchExact},
+		{"bmw", []string{"Motors BMW", "BMW Motors"}, matchPrefix},
+		{"مرسيدس", []string{"Mercedes", "مرسيدس بنز"}, matchPrefix},
+	}
+
+	for _, test := range tests {
+		if got := matchQuality(test.text, test.names); got != test.want {
+			t.Errorf("matchQuality(%q, %q) = %d, want %d", test.text, test.names, got, test.want)
+		}
+	}
+}
+
+func TestNormalizeSearchText(t *testing.T) {
+	tests := []struct {
+		text, want string
+	}{
+		{"BMW", "bmw"},
+		{"  Mercedes\tBenz \n C-Class ", "mercedes benz c-class"},
+		{"", ""},
+	}
+
+	for _, test := range tests {
+		if got := normalizeSearchText(test.text); got != test.want {
+			t.Errorf("normalizeSearchText(%q) = %q, want %q", test.text, got, test.want)
+		}
+	}
+}
+
+func TestRankSearchCandidates(t *testing.T) {
+	candidate := func(searchType, title string, names ...string) searchCandidate {
+		return searchCandidate{
+			result: models.SearchResult{Type: searchType, ID: searchType + ":" + title, Title: title},
+			names:  append([]string{title}, names...),
+		}
+	}
+
+	tests := []struct {
+		name       string
+		text       string
+		candidates []searchCandidate
+		want       []string
+	}{
+		{
+			"quality first",
+			"x5",
+			[]searchCandidate{
+				candidate(models.SearchTypeBrand, "Tx5"),
+				candidate(models.SearchTypeVariant, "BMW X5 xDrive40i"),
+				candidate(models.SearchTypeShowroom, "X5"),
+				candidate(models.SearchTypeModel, "X5 M"),
+			},
+			[]string{"showroom:X5", "model:X5 M", "variant:BMW X5 xDrive40i", "brand:Tx5"},
+		},
+		{
+			"then type",
+			"bmw",
+			[]searchCandidate{
+				candidate(models.SearchTypeShowroom, "BMW"),
+				candidate(models.SearchTypeModel, "BMW"),
+				candidate(models.SearchTypeBrand, "BMW"),
+			},
+			[]string{"brand:BMW", "model:BMW", "showroom:BMW"},
+		},
+		{
+			"then title length and title",
+			"x",
+			[]searchCandidate{
+				candidate(models.SearchTypeModel, "X5 M"),
+				candidate(models.SearchTypeModel, "X6"),
+				candidate(models.SearchTypeModel, "X5"),
+			},
+			[]string{"model:X5", "model:X6", "model:X5 M"},
+		},
+		{
+			"other localizations count",
+			"مرسيدس",
+			[]searchCandidate{
+				candidate(models.SearchTypeModel, "Benz Mercedes", "بنز مرسيدس"),
+				candidate(models.SearchTypeBrand, "Mercedes-Benz", "مرسيدس بنز"),
+				candidate(models.SearchTypeBrand, "Daimler"),
+			},
+			[]string{"brand:Mercedes-Benz", "model:Benz Mercedes", "brand:Daimler"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			rankSearchCandidates(test.text, test.candidates)
+			got := make([]string, len(test.candidates))
+			for i, candidate := range test.candidates {
+				got[i] = candidate.result.ID
+			}
+			if !reflect.DeepEqual(got, test.want) {
+				t.Errorf("ranked %q, want %q", got, test.want)
+			}
+		})
+	}
+}
@@ -19,61 +19,6 @@ func NewShowroomServiceGraphQL(client *CMSClient) *ShowroomServiceGraphQL {
 	}
 }
 
-// parseMediaField converts Strapi media field to our MediaField model with URL prefixing
-func (s *ShowroomServiceGraphQL) parseMediaField(media *strapiMediaField) *models.MediaField {
-	if media == nil {
-		return nil
-	}
-
-	field := &models.MediaField{
-		ID:     media.DocumentID,
-		Width:  media.Width,
-		Height: media.Height,
-		URL:    s.client.PrefixMediaURL(media.URL),
-	}
-
-	// Parse formats if available
-	if media.Formats != nil {
-		formats := &models.MediaFormats{}
-
-		// Helper to parse individual format
-		parseFormat := func(formatData interface{}) *models.MediaFormat {
-			if formatData == nil {
-				return nil
-			}
-			formatMap, ok := formatData.(map[string]interface{})
-			if !ok {
-				return nil
-			}
-			url, _ := formatMap["url"].(string)
-			width, _ := formatMap["width"].(float64)
-			height, _ := formatMap["height"].(float64)
-			return &models.MediaFormat{
-				Width:  int(width),
-				Height: int(height),
-				URL:    s.client.PrefixMediaURL(url),
-			}
-		}
-
-		if thumbnail, ok := media.Formats["thumbnail"]; ok {
-			formats.Thumbnail = parseFormat(thumbnail)
-		}
-		if small, ok := media.Formats["small"]; ok {
-			formats.Small = parseFormat(small)
-		}
-		if medium, ok := media.Formats["medium"]; ok {
-			formats.Medium = parseFormat(medium)
-		}
-		if large, ok := media.Formats["large"]; ok {
-			formats.Large = parseFormat(large)
-		}
-
-		field.Formats = formats
-	}
-
-	return field
-}
-
 // GetAll fetches all showrooms
 func (s *ShowroomServiceGraphQL) GetAll(ctx context.Context) ([]models.Showroom, error) {
 	data, err := s.client.ExecuteGraphQL(ctx, GetShowroomsQuery, nil)
@@ -81,19 +26,9 @@ func (s *ShowroomServiceGraphQL) GetAll(ctx context.Context) ([]models.Showroom,
 		return nil, fmt.Errorf("failed to fetch showrooms: %w", err)
 	}
 
-	var result struct {
-		Showrooms []struct {
-			DocumentID  string            `json:"documentId"`
-			Name        string            `json:"Name"`
-			Description string            `json:"Description"`
-			IsVerified  bool              `json:"IsVerified"`
-			IsFeatured  bool              `json:"IsFeatured"`
-			Logo        *strapiMediaField `json:"Logo"`
-		} `json:"showrooms"`
-	}
-
+	var result getShowroomsResult
 	if err := json.Unmarshal(data, &result); err != nil {
-		return nil, fmt.Errorf("failed to unmarshal showrooms: %w", err)
+		return nil, newDecodeError("showrooms", err)
 	}
 
 	showrooms := make([]models.Showroom, len(result.Showrooms))
@@ -104,7 +39,7 @@ func (s *ShowroomServiceGraphQL) GetAll(ctx context.Context) ([]models.Showroom,
 			Description: showroom.Description,
 			IsVerified:  showroom.IsVerified,
 			IsFeatured:  showroom.IsFeatured,
-			Logo:        s.parseMediaField(showroom.Logo),
+			Logo:        s.client.media.Field(showroom.Logo),
 		}
 	}
 
@@ -122,49 +57,13 @@ func (s *ShowroomServiceGraphQL) GetByID(ctx context.Context, documentId string)
 		return nil, fmt.Errorf("failed to fetch showroom: %w", err)
 	}
 
-	var result struct {
-		Showroom *struct {
-			DocumentID     string            `json:"documentId"`
-			Name           string            `json:"Name"`
-			Description    string            `json:"Description"`
-			IsVerified     bool              `json:"IsVerified"`
-			IsFeatured     bool              `json:"IsFeatured"`
-			Logo           *strapiMediaField `json:"Logo"`
-			Cover          *strapiMediaField `json:"Cover"`
-			OperatingHours string            `json:"OperatingHours"`
-			Location       *struct {
-				Address     string   `json:"Address"`
-				Latitude    float64  `json:"Latitude"`
-				Longitude   float64  `json:"Longitude"`
-				Governorate *struct {
-					DocumentID string `json:"documentId"`
-					Name       string `json:"Name"`
-				} `json:"governorate"`
-				City *struct {
-					DocumentID string `json:"documentId"`
-					Name       string `json:"Name"`
-				} `json:"city"`
-			} `json:"Location"`
-			ContactInfo *struct {
-				Email      string `json:"Email"`
-				Phone      string `json:"Phone"`
-				Facebook   string `json:"Facebook"`
-				Instagram  string `json:"Instagram"`
-				Tiktok     string `json:"Tiktok"`
-				Whatsapp   string `json:"Whatsapp"`
-				X          string `json:"X"`
-				Youtube    string `json:"Youtube"`
-				WebsiteURL string `json:"WebsiteURL"`
-			} `json:"ContactInfo"`
-		} `json:"showroom"`
-	}
-
+	var result getShowroomProfileResult
 	if err := json.Unmarshal(data, &result); err != nil {
-		return nil, fmt.Errorf("failed to unmarshal showroom: %w", err)
+		return nil, newDecodeError("showroom", err)
 	}
 
 	if result.Showroom == nil {
-		return nil, fmt.Errorf("showroom not found")
+		return nil, newNotFoundError("showroom")
 	}
 
 	showroom := &models.DetailedShowroom{
@@ -173,8 +72,8 @@ func (s *ShowroomServiceGraphQL) GetByID(ctx context.Context, documentId string)
 		Description:    result.Showroom.Description,
 		IsVerified:     result.Showroom.IsVerified,
 		IsFeatured:     result.Showroom.IsFeatured,
-		Logo:           s.parseMediaField(result.Showroom.Logo),
-		Cover:          s.parseMediaField(result.Showroom.Cover),
+		Logo:           s.client.media.Field(result.Showroom.Logo),
+		Cover:          s.client.media.Field(result.Showroom.Cover),
 		OperatingHours: result.Showroom.OperatingHours,
 	}
 
@@ -232,21 +131,9 @@ func (s *ShowroomServiceGraphQL) GetCarVariantsByShowroomID(ctx context.Context,
 		return nil, fmt.Errorf("failed to fetch variants: %w", err)
 	}
 
-	var result struct {
-		CarVariants []struct {
-			DocumentID      string             `json:"documentId"`
-			DisplayName     string             `json:"DisplayName"`
-			Images          []strapiMediaField `json:"Images"`
-			ShowroomPricing []struct {
-				Price           int `json:"Price"`
-				MinDownPayment  int `json:"MinimuDownpayment"`
-				MinInstallments int `json:"MinimumInstallements"`
-			} `json:"ShowroomPricing"`
-		} `json:"carVariants"`
-	}
-
+	var result getCarVariantsByShowroomResult
 	if err := json.Unmarshal(data, &result); err != nil {
-		return nil, fmt.Errorf("failed to unmarshal variants: %w", err)
+		return nil, newDecodeError("variants", err)
 	}
 
 	variants := make([]models.ShowroomVariant, 0, len(result.CarVariants))
@@ -258,10 +145,7 @@ func (s *ShowroomServiceGraphQL) GetCarVariantsByShowroomID(ctx context.Context,
 
 		// Parse images
 		if len(variant.Images) > 0 {
-			images := make(models.MediaCollectionField, len(variant.Images))
-			for i, img := range variant.Images {
-				images[i] = *s.parseMediaField(&img)
-			}
+			images := s.client.media.Collection(variant.Images)
 			showroomVariant.Images = &images
 		}
 
@@ -269,8 +153,8 @@ func (s *ShowroomServiceGraphQL) GetCarVariantsByShowroomID(ctx context.Context,
 		if len(variant.ShowroomPricing) > 0 {
 			pricing := variant.ShowroomPricing[0]
 			showroomVariant.Price = pricing.Price
-			showroomVariant.MinDownPayment = pricing.MinDownPayment
-			showroomVariant.MinInstallments = pricing.MinInstallments
+			showroomVariant.MinDownPayment = intValue(pricing.MinimumDownPayment)
+			showroomVariant.MinInstallments = intValue(pricing.MinimumInstallments)
 		}
 
 		variants = append(variants, showroomVariant)
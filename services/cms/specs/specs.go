@@ -0,0 +1,250 @@
+// Package specs describes the specs of car variants: their stable keys, localized labels,
+// units, types and display groups, and how their values are formatted
+package specs
+
+import (
+	"api-gateway/services/cms/models"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// fallbackLanguage is used for labels missing in the requested language
+const fallbackLanguage = "en"
+
+// Type is how a spec's value is interpreted
+type Type string
+
+const (
+	TypeNumber Type = "number" // A quantity in the spec's unit
+	TypeEnum   Type = "enum"   // One of the spec's options; unknown values are shown as entered
+	TypeText   Type = "text"   // Free text
+)
+
+// Labels holds a text per language, e.g. {"en": "Height", "ar": "الارتفاع"}
+type Labels map[string]string
+
+// Get returns the text for a locale such as ar or ar-EG, falling back to English
+func (l Labels) Get(locale string) string {
+	language, _, _ := strings.Cut(strings.ReplaceAll(strings.ToLower(locale), "_", "-"), "-")
+	if text, ok := l[language]; ok {
+		return text
+	}
+	return l[fallbackLanguage]
+}
+
+// Group is a section specs are displayed in
+type Group struct {
+	Key    string
+	Labels Labels
+}
+
+// Display groups, in display order
+var (
+	GroupEngine      = Group{"engine", Labels{"en": "Engine", "ar": "المحرك"}}
+	GroupPerformance = Group{"performance", Labels{"en": "Performance", "ar": "الأداء"}}
+	GroupDimensions  = Group{"dimensions", Labels{"en": "Dimensions", "ar": "الأبعاد"}}
+	GroupCapacity    = Group{"capacity", Labels{"en": "Capacity", "ar": "السعة"}}
+	GroupOrigin      = Group{"origin", Labels{"en": "Origin", "ar": "المنشأ"}}
+)
+
+// Unit is the unit of number specs
+type Unit struct {
+	Key     string // Stable symbol, e.g. mm
+	Symbols Labels // Localized symbol shown after values
+}
+
+// Units of number specs
+var (
+	UnitMillimeter       = Unit{"mm", Labels{"en": "mm", "ar": "مم"}}
+	UnitKilometerPerHour = Unit{"km/h", Labels{"en": "km/h", "ar": "كم/س"}}
+	UnitHorsepower       = Unit{"hp", Labels{"en": "hp", "ar": "حصان"}}
+	UnitSecond           = Unit{"s", Labels{"en": "s", "ar": "ث"}}
+	UnitLiterPer100Km    = Unit{"L/100km", Labels{"en": "L/100 km", "ar": "لتر/100 كم"}}
+	UnitLiter            = Unit{"L", Labels{"en": "L", "ar": "لتر"}}
+)
+
+// Option is a known value of an enum spec
+type Option struct {
+	Value   string   // Canonical value, e.g. automatic
+	Aliases []string // Other spellings editors use, matched ignoring case, spaces and dashes
+	Labels  Labels
+}
+
+// Options of the enum specs
+var (
+	TransmissionOptions = []Option{
+		{Value: "automatic", Aliases: []string{"auto", "at", "أوتوماتيك", "اوتوماتيك"}, Labels: Labels{"en": "Automatic", "ar": "أوتوماتيك"}},
+		{Value: "manual", Aliases: []string{"mt", "مانيوال", "يدوي"}, Labels: Labels{"en": "Manual", "ar": "مانيوال"}},
+		{Value: "cvt", Labels: Labels{"en": "CVT", "ar": "CVT"}},
+		{Value: "dct", Aliases: []string{"dsg", "dualclutch"}, Labels: Labels{"en": "Dual-clutch", "ar": "ثنائي القابض"}},
+	}
+	DrivetrainOptions = []Option{
+		{Value: "fwd", Aliases: []string{"front", "fronttraction", "frontwheeldrive", "دفعأمامي"}, Labels: Labels{"en": "Front-wheel drive", "ar": "دفع أمامي"}},
+		{Value: "rwd", Aliases: []string{"rear", "reartraction", "rearwheeldrive", "دفعخلفي"}, Labels: Labels{"en": "Rear-wheel drive", "ar": "دفع خلفي"}},
+		{Value: "awd", Aliases: []string{"allwheeldrive", "دفعكلي"}, Labels: Labels{"en": "All-wheel drive", "ar": "دفع كلي"}},
+		{Value: "4wd", Aliases: []string{"4x4", "fourwheeldrive", "دفعرباعي"}, Labels: Labels{"en": "Four-wheel drive", "ar": "دفع رباعي"}},
+	}
+)
+
+// Value is a spec's value as read from Strapi; the zero Value is unset
+type Value struct {
+	number *float64
+	text   string
+}
+
+// Int is the value of a nullable Int field
+func Int(v *int) Value {
+	if v == nil {
+		return Value{}
+	}
+	number := float64(*v)
+	return Value{number: &number}
+}
+
+// Float is the value of a nullable Float field
+func Float(v *float64) Value {
+	if v == nil {
+		return Value{}
+	}
+	number := *v
+	return Value{number: &number}
+}
+
+// Required is the value of a non-nullable number field. Strapi returns null, decoded as 0,
+// for entries saved before the field became required, so 0 is unset.
+func Required[N int | float64](v N) Value {
+	if v == 0 {
+		return Value{}
+	}
+	number := float64(v)
+	return Value{number: &number}
+}
+
+// Text is the value of a String field; a blank one is unset
+func Text(v string) Value {
+	return Value{text: strings.TrimSpace(v)}
+}
+
+// Spec describes one field of Strapi's ComponentRepeatablesSpecs, read from a decoded
+// component of type T
+type Spec[T any] struct {
+	Key      string // Stable key exposed to clients
+	Labels   Labels
+	Group    Group
+	Type     Type
+	Unit     *Unit         // Number specs without a unit are plain counts
+	Decimals int           // Decimal places of number specs
+	Options  []Option      // Enum specs
+	Value    func(T) Value // Reads the field
+}
+
+// Items turns a specs component into spec items in catalog order, labeled and formatted
+// for a locale. Unset specs are skipped.
+func Items[T any](catalog []Spec[T], component T, locale string) []models.SpecItem {
+	items := make([]models.SpecItem, 0, len(catalog))
+	for _, spec := range catalog {
+		item, ok := spec.item(spec.Value(component), locale)
+		if !ok {
+			continue
+		}
+		item.ID = len(items) + 1
+		items = append(items, item)
+	}
+	return items
+}
+
+// item describes a value of the spec; ok is false for unset values
+func (spec Spec[T]) item(value Value, locale string) (item models.SpecItem, ok bool) {
+	item = models.SpecItem{
+		Key:        spec.Key,
+		Label:      spec.Labels.Get(locale),
+		Type:       string(spec.Type),
+		Group:      spec.Group.Key,
+		GroupLabel: spec.Group.Labels.Get(locale),
+	}
+
+	switch {
+	case value.number != nil:
+		number := *value.number
+		if spec.Type != TypeNumber {
+			item.Value = strconv.FormatFloat(number, 'f', -1, 64)
+			return item, true
+		}
+		item.Number = &number
+		item.Value = spec.formatNumber(number, locale)
+		if spec.Unit != nil {
+			item.Unit = spec.Unit.Key
+		}
+	case value.text != "":
+		item.Value = value.text
+		if option := spec.option(value.text); option != nil {
+			item.Enum = option.Value
+			item.Value = option.Labels.Get(locale)
+		}
+	default:
+		return item, false
+	}
+	return item, true
+}
+
+// formatNumber rounds a value to the spec's decimals, groups thousands and appends the
+// localized unit, e.g. 1,850 mm
+func (spec Spec[T]) formatNumber(value float64, locale string) string {
+	scale := math.Pow(10, float64(spec.Decimals))
+	formatted := strconv.FormatFloat(math.Round(value*scale)/scale, 'f', -1, 64)
+
+	whole, fraction, _ := strings.Cut(formatted, ".")
+	sign := ""
+	if strings.HasPrefix(whole, "-") {
+		sign, whole = "-", whole[1:]
+	}
+	var grouped strings.Builder
+	for i, digit := range whole {
+		if i > 0 && (len(whole)-i)%3 == 0 {
+			grouped.WriteByte(',')
+		}
+		grouped.WriteRune(digit)
+	}
+	formatted = sign + grouped.String()
+	if fraction != "" {
+		formatted += "." + fraction
+	}
+
+	if spec.Unit != nil {
+		formatted += " " + spec.Unit.Symbols.Get(locale)
+	}
+	return formatted
+}
+
+// option returns the option of an enum spec that a value spells, or nil
+func (spec Spec[T]) option(value string) *Option {
+	normalized := normalizeOption(value)
+	for i, option := range spec.Options {
+		if normalizeOption(option.Value) == normalized {
+			return &spec.Options[i]
+		}
+		for _, alias := range option.Aliases {
+			if normalizeOption(alias) == normalized {
+				return &spec.Options[i]
+			}
+		}
+		for _, label := range option.Labels {
+			if normalizeOption(label) == normalized {
+				return &spec.Options[i]
+			}
+		}
+	}
+	return nil
+}
+
+// normalizeOption lower-cases a value and drops spaces, dashes and underscores
+func normalizeOption(value string) string {
+	return strings.Map(func(r rune) rune {
+		switch r {
+		case ' ', '-', '_':
+			return -1
+		}
+		return r
+	}, strings.ToLower(value))
+}
@@ -0,0 +1,214 @@
+package specs
+
+import (
+	"api-gateway/services/cms/models"
+	"reflect"
+	"testing"
+)
+
+type testComponent struct {
+	Height       *int
+	Acceleration *float64
+	Seats        int
+	Transmission string
+	Origin       string
+}
+
+var testCatalog = []Spec[testComponent]{
+	{
+		Key: "height", Labels: Labels{"en": "Height", "ar": "الارتفاع"}, Group: GroupDimensions, Type: TypeNumber, Unit: &UnitMillimeter,
+		Value: func(c testComponent) Value { return Int(c.Height) },
+	},
+	{
+		Key: "acceleration", Labels: Labels{"en": "0-100 km/h"}, Group: GroupPerformance, Type: TypeNumber, Unit: &UnitSecond, Decimals: 1,
+		Value: func(c testComponent) Value { return Float(c.Acceleration) },
+	},
+	{
+		Key: "seats", Labels: Labels{"en": "Seats", "ar": "المقاعد"}, Group: GroupCapacity, Type: TypeNumber,
+		Value: func(c testComponent) Value { return Required(c.Seats) },
+	},
+	{
+		Key: "transmission", Labels: Labels{"en": "Transmission", "ar": "ناقل الحركة"}, Group: GroupEngine, Type: TypeEnum, Options: TransmissionOptions,
+		Value: func(c testComponent) Value { return Text(c.Transmission) },
+	},
+	{
+		Key: "origin", Labels: Labels{"en": "Origin", "ar": "بلد المنشأ"}, Group: GroupOrigin, Type: TypeText,
+		Value: func(c testComponent) Value { return Text(c.Origin) },
+	},
+}
+
+func TestItems(t *testing.T) {
+	height, acceleration := 1650, 6.25
+	number := func(v float64) *float64 { return &v }
+
+	tests := []struct {
+		name      string
+		component testComponent
+		locale    string
+		want      []models.SpecItem
+	}{
+		{
+			"unset specs are skipped",
+			testComponent{Transmission: "  "},
+			"en",
+			[]models.SpecItem{},
+		},
+		{
+			"english",
+			testComponent{Height: &height, Acceleration: &acceleration, Seats: 5, Transmission: "AT", Origin: " Germany "},
+			"en",
+			[]models.SpecItem{
+				{ID: 1, Key: "height", Label: "Height", Value: "1,650 mm", Type: "number", Group: "dimensions", GroupLabel: "Dimensions", Unit: "mm", Number: number(1650)},
+				{ID: 2, Key: "acceleration", Label: "0-100 km/h", Value: "6.3 s", Type: "number", Group: "performance", GroupLabel: "Performance", Unit: "s", Number: number(6.25)},
+				{ID: 3, Key: "seats", Label: "Seats", Value: "5", Type: "number", Group: "capacity", GroupLabel: "Capacity", Number: number(5)},
+				{ID: 4, Key: "transmission", Label: "Transmission", Value: "Automatic", Type: "enum", Group: "engine", GroupLabel: "Engine", Enum: "automatic"},
+				{ID: 5, Key: "origin", Label: "Origin", Value: "Germany", Type: "text", Group: "origin", GroupLabel: "Origin"},
+			},
+		},
+		{
+			"arabic with ids after skipped specs",
+			testComponent{Height: &height, Transmission: "Dual Clutch", Origin: "ألمانيا"},
+			"ar-EG",
+			[]models.SpecItem{
+				{ID: 1, Key: "height", Label: "الارتفاع", Value: "1,650 مم", Type: "number", Group: "dimensions", GroupLabel: "الأبعاد", Unit: "mm", Number: number(1650)},
+				{ID: 2, Key: "transmission", Label: "ناقل الحركة", Value: "ثنائي القابض", Type: "enum", Group: "engine", GroupLabel: "المحرك", Enum: "dct"},
+				{ID: 3, Key: "origin", Label: "بلد المنشأ", Value: "ألمانيا", Type: "text", Group: "origin", GroupLabel: "المنشأ"},
+			},
+		},
+		{
+			"unknown enum values are shown as entered",
+			testComponent{Transmission: "e-CVT hybrid"},
+			"ar",
+			[]models.SpecItem{
+				{ID: 1, Key: "transmission", Label: "ناقل الحركة", Value: "e-CVT hybrid", Type: "enum", Group: "engine", GroupLabel: "المحرك"},
+			},
+		},
+		{
+			"labels missing in a language fall back to english",
+			testComponent{Acceleration: &acceleration},
+			"ar",
+			[]models.SpecItem{
+				{ID: 1, Key: "acceleration", Label: "0-100 km/h", Value: "6.3 ث", Type: "number", Group: "performance", GroupLabel: "الأداء", Unit: "s", Number: number(6.25)},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got := Items(testCatalog, test.component, test.locale)
+			if !reflect.DeepEqual(got, test.want) {
+				t.Errorf("Items() =\n%+v\nwant\n%+v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestLabelsGet(t *testing.T) {
+	labels := Labels{"en": "Height", "ar": "الارتفاع"}
+
+	tests := []struct {
+		locale, want string
+	}{
+		{"en", "Height"},
+		{"ar", "الارتفاع"},
+		{"ar-EG", "الارتفاع"},
+		{"AR_eg", "الارتفاع"},
+		{"fr", "Height"},
+		{"", "Height"},
+	}
+
+	for _, test := range tests {
+		if got := labels.Get(test.locale); got != test.want {
+			t.Errorf("Get(%q) = %q, want %q", test.locale, got, test.want)
+		}
+	}
+}
+
+func TestFormatNumber(t *testing.T) {
+	plain := Spec[testComponent]{}
+	millimeters := Spec[testComponent]{Unit: &UnitMillimeter}
+	liters := Spec[testComponent]{Unit: &UnitLiterPer100Km, Decimals: 1}
+
+	tests := []struct {
+		spec   Spec[testComponent]
+		value  float64
+		locale string
+		want   string
+	}{
+		{plain, 0, "en", "0"},
+		{plain, 999, "en", "999"},
+		{plain, 1000, "en", "1,000"},
+		{plain, 1234567, "en", "1,234,567"},
+		{plain, -1234, "en", "-1,234"},
+		{plain, 2.4, "en", "2"},
+		{millimeters, 4850, "en", "4,850 mm"},
+		{millimeters, 4850, "ar", "4,850 مم"},
+		{liters, 7.25, "en", "7.3 L/100 km"},
+		{liters, 8, "ar", "8 لتر/100 كم"},
+		{liters, 1234.56, "en", "1,234.6 L/100 km"},
+	}
+
+	for _, test := range tests {
+		if got := test.spec.formatNumber(test.value, test.locale); got != test.want {
+			t.Errorf("formatNumber(%v, %q) = %q, want %q", test.value, test.locale, got, test.want)
+		}
+	}
+}
+
+func TestOption(t *testing.T) {
+	transmission := Spec[testComponent]{Options: TransmissionOptions}
+	drivetrain := Spec[testComponent]{Options: DrivetrainOptions}
+
+	tests := []struct {
+		spec  Spec[testComponent]
+		value string
+		want  string // Canonical value, empty for none
+	}{
+		{transmission, "automatic", "automatic"},
+		{transmission, "AT", "automatic"},
+		{transmission, "أوتوماتيك", "automatic"},
+		{transmission, "Dual-Clutch", "dct"},
+		{transmission, "DSG", "dct"},
+		{transmission, "steptronic", ""},
+		{drivetrain, "4x4", "4wd"},
+		{drivetrain, "Front Wheel Drive", "fwd"},
+		{drivetrain, "دفع خلفي", "rwd"},
+		{drivetrain, "all_wheel_drive", "awd"},
+	}
+
+	for _, test := range tests {
+		got := ""
+		if option := test.spec.option(test.value); option != nil {
+			got = option.Value
+		}
+		if got != test.want {
+			t.Errorf("option(%q) = %q, want %q", test.value, got, test.want)
+		}
+	}
+}
+
+func TestValues(t *testing.T) {
+	zero, half := 0, 0.5
+
+	tests := []struct {
+		name  string
+		value Value
+		unset bool
+	}{
+		{"nil int", Int(nil), true},
+		{"zero int", Int(&zero), false},
+		{"nil float", Float(nil), true},
+		{"float", Float(&half), false},
+		{"zero required int", Required(0), true},
+		{"zero required float", Required(0.0), true},
+		{"required int", Required(4), false},
+		{"blank text", Text(" \t"), true},
+		{"text", Text("Japan"), false},
+	}
+
+	for _, test := range tests {
+		if unset := test.value == (Value{}); unset != test.unset {
+			t.Errorf("%s: unset = %v, want %v", test.name, unset, test.unset)
+		}
+	}
+}
@@ -0,0 +1,150 @@
+package cms
+
+import (
+	"api-gateway/services/cms/models"
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"golang.org/x/sync/errgroup"
+)
+
+// Number of variants a comparison takes
+const (
+	compareMinVariants = 2
+	compareMaxVariants = 4
+)
+
+// ParseComparedVariants parses the comma-separated variant documentIds of the compare
+// endpoint; duplicates are dropped
+func ParseComparedVariants(raw string) ([]string, error) {
+	var ids []string
+	for _, id := range strings.Split(raw, ",") {
+		if id = strings.TrimSpace(id); id != "" && !slices.Contains(ids, id) {
+			ids = append(ids, id)
+		}
+	}
+	if len(ids) < compareMinVariants || len(ids) > compareMaxVariants {
+		return nil, newBadInputError(fmt.Sprintf("variants must list %d to %d distinct variant ids", compareMinVariants, compareMaxVariants))
+	}
+	return ids, nil
+}
+
+// CompareVariants fetches variants concurrently and aligns their specs (matched by key) and
+// features (matched by label) into rows, in the order they first appear
+func (s *CarModelServiceGraphQL) CompareVariants(ctx context.Context, variantDocumentIDs []string) (*models.VariantComparison, error) {
+	variants := make([]*models.DetailedVariant, len(variantDocumentIDs))
+	group, groupCtx := errgroup.WithContext(ctx)
+	for i, id := range variantDocumentIDs {
+		group.Go(func() error {
+			variant, err := s.GetVariantByID(groupCtx, id)
+			if errors.Is(err, ErrNotFound) {
+				return newNotFoundError("variant " + id)
+			}
+			variants[i] = variant
+			return err
+		})
+	}
+	if err := group.Wait(); err != nil {
+		return nil, err
+	}
+
+	comparison := &models.VariantComparison{
+		Variants: make([]models.ComparedVariant, len(variants)),
+	}
+	specs := newComparisonTable(len(variants))
+	features := newComparisonTable(len(variants))
+	for i, variant := range variants {
+		compared := models.ComparedVariant{
+			ID:          variant.ID,
+			Title:       variant.Title,
+			Model:       variant.Model,
+			Price:       variant.PriceFrom,
+			MarketPrice: variant.MarketPriceFrom,
+		}
+		if variant.Images != nil && len(*variant.Images) > 0 {
+			compared.Thumbnail = &(*variant.Images)[0]
+		}
+		for _, showroom := range variant.Showrooms {
+			if showroom.Price <= 0 {
+				continue
+			}
+			if cheapest := compared.CheapestShowroom; cheapest == nil || showroom.Price < cheapest.Price ||
+				(showroom.Price == cheapest.Price && showroom.ID < cheapest.ID) {
+				compared.CheapestShowroom = &showroom
+			}
+		}
+		comparison.Variants[i] = compared
+
+		for _, spec := range variant.Specs {
+			specs.set(spec.Key, spec.Label, i, spec.Value)
+		}
+		for _, feature := range variant.Features {
+			features.set("", feature.Label, i, feature.Value)
+		}
+	}
+	comparison.Specs = specs.rows()
+	comparison.Features = features.rows()
+
+	return comparison, nil
+}
+
+// comparisonTable collects the rows of a comparison. Rows without a stable key are matched by
+// label, ignoring case and extra whitespace, since labels come from each variant's content.
+type comparisonTable struct {
+	columns int
+	order   []string
+	byKey   map[string]*models.ComparisonRow
+}
+
+// newComparisonTable creates a table with one column per variant
+func newComparisonTable(columns int) *comparisonTable {
+	return &comparisonTable{columns: columns, byKey: make(map[string]*models.ComparisonRow)}
+}
+
+// set stores the value of a row for the variant in column; key may be empty
+func (t *comparisonTable) set(key, label string, column int, value string) {
+	match := key
+	if match == "" {
+		match = "label:" + strings.ToLower(strings.Join(strings.Fields(label), " "))
+		if match == "label:" {
+			return
+		}
+	}
+	row, ok := t.byKey[match]
+	if !ok {
+		row = &models.ComparisonRow{Key: key, Label: label, Values: make([]*string, t.columns)}
+		t.byKey[match] = row
+		t.order = append(t.order, match)
+	}
+	if row.Values[column] == nil {
+		row.Values[column] = &value
+	}
+}
+
+// rows returns the rows in the order their labels first appeared, with Differs set
+func (t *comparisonTable) rows() []models.ComparisonRow {
+	rows := make([]models.ComparisonRow, 0, len(t.order))
+	for _, key := range t.order {
+		row := *t.byKey[key]
+		for _, value := range row.Values[1:] {
+			if !sameComparisonValue(row.Values[0], value) {
+				row.Differs = true
+				break
+			}
+		}
+		rows = append(rows, row)
+	}
+	return rows
+}
+
+// sameComparisonValue reports whether two cells hold the same value; missing cells only
+// equal missing cells
+func sameComparisonValue(a, b *string) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return strings.EqualFold(strings.TrimSpace(*a), strings.TrimSpace(*b))
+}
@@ -0,0 +1,151 @@
+package cms
+
+import (
+	"api-gateway/services/cms/models"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestParseComparedVariants(t *testing.T) {
+	tests := []struct {
+		raw     string
+		want    []string
+		wantErr bool
+	}{
+		{"a,b", []string{"a", "b"}, false},
+		{" a , b ,c,d ", []string{"a", "b", "c", "d"}, false},
+		{"a,a,b", []string{"a", "b"}, false},
+		{"a,,b,", []string{"a", "b"}, false},
+		{"", nil, true},
+		{"a", nil, true},
+		{"a,a", nil, true},
+		{"a,b,c,d,e", nil, true},
+	}
+
+	for _, test := range tests {
+		got, err := ParseComparedVariants(test.raw)
+		if test.wantErr {
+			if !errors.Is(err, ErrBadInput) {
+				t.Errorf("ParseComparedVariants(%q) error = %v, want ErrBadInput", test.raw, err)
+			}
+			continue
+		}
+		if err != nil || !reflect.DeepEqual(got, test.want) {
+			t.Errorf("ParseComparedVariants(%q) = %q, %v; want %q", test.raw, got, err, test.want)
+		}
+	}
+}
+
+func TestComparisonTable(t *testing.T) {
+	type cell struct {
+		key, label string
+		column     int
+		value      string
+	}
+	tests := []struct {
+		name  string
+		cells []cell
+		want  []models.ComparisonRow
+	}{
+		{
+			"specs match by key and keep the first label",
+			[]cell{{"power", "Power", 0, "150 hp"}, {"power", "Horsepower", 1, "190 hp"}},
+			[]models.ComparisonRow{{Key: "power", Label: "Power", Values: values("150 hp", "190 hp"), Differs: true}},
+		},
+		{
+			"features match by label ignoring case and whitespace",
+			[]cell{{"", "Sunroof", 0, "Yes"}, {"", "  sunROOF ", 1, "yes "}},
+			[]models.ComparisonRow{{Label: "Sunroof", Values: values("Yes", "yes ")}},
+		},
+		{
+			"missing cells differ from present ones",
+			[]cell{{"", "Sunroof", 0, "Yes"}},
+			[]models.ComparisonRow{{Label: "Sunroof", Values: []*string{ptr("Yes"), nil}, Differs: true}},
+		},
+		{
+			"rows keep first appearance order",
+			[]cell{{"", "B", 1, "1"}, {"", "A", 0, "1"}, {"", "b", 0, "1"}},
+			[]models.ComparisonRow{
+				{Label: "B", Values: values("1", "1")},
+				{Label: "A", Values: []*string{ptr("1"), nil}, Differs: true},
+			},
+		},
+		{
+			"first value per cell wins",
+			[]cell{{"k", "K", 0, "first"}, {"k", "K", 0, "second"}, {"k", "K", 1, "first"}},
+			[]models.ComparisonRow{{Key: "k", Label: "K", Values: values("first", "first")}},
+		},
+		{
+			"blank feature labels are skipped",
+			[]cell{{"", "   ", 0, "Yes"}},
+			[]models.ComparisonRow{},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			table := newComparisonTable(2)
+			for _, c := range test.cells {
+				table.set(c.key, c.label, c.column, c.value)
+			}
+			if got := table.rows(); !reflect.DeepEqual(got, test.want) {
+				t.Errorf("rows() = %s, want %s", formatRows(got), formatRows(test.want))
+			}
+		})
+	}
+}
+
+func TestSameComparisonValue(t *testing.T) {
+	tests := []struct {
+		a, b *string
+		want bool
+	}{
+		{nil, nil, true},
+		{ptr("x"), nil, false},
+		{nil, ptr(""), false},
+		{ptr("Automatic"), ptr(" automatic"), true},
+		{ptr("150 hp"), ptr("190 hp"), false},
+	}
+
+	for _, test := range tests {
+		if got := sameComparisonValue(test.a, test.b); got != test.want {
+			t.Errorf("sameComparisonValue(%s, %s) = %v, want %v", formatValue(test.a), formatValue(test.b), got, test.want)
+		}
+	}
+}
+
+func ptr(s string) *string {
+	return &s
+}
+
+func values(values ...string) []*string {
+	result := make([]*string, len(values))
+	for i := range values {
+		result[i] = &values[i]
+	}
+	return result
+}
+
+func formatValue(value *string) string {
+	if value == nil {
+		return "nil"
+	}
+	return `"` + *value + `"`
+}
+
+func formatRows(rows []models.ComparisonRow) string {
+	s := "["
+	for _, row := range rows {
+		s += "{" + row.Key + " " + row.Label + " ["
+		for _, value := range row.Values {
+			s += formatValue(value) + " "
+		}
+		s += "]"
+		if row.Differs {
+			s += " differs"
+		}
+		s += "}"
+	}
+	return s + "]"
+}
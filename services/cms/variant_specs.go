@@ -0,0 +1,47 @@
+package cms
+
+import (
+	"api-gateway/services/cms/specs"
+)
+
+// variantSpecs is a variant's ComponentRepeatablesSpecs as selected by GetCarVariantByIDQuery
+type variantSpecs = getCarVariantCarVariantSpecs
+
+// variantSpecCatalog lists every known spec in display order
+var variantSpecCatalog = []specs.Spec[*variantSpecs]{
+	{Key: "engine", Labels: specs.Labels{"en": "Engine", "ar": "المحرك"}, Group: specs.GroupEngine, Type: specs.TypeText,
+		Value: func(s *variantSpecs) specs.Value { return specs.Text(s.Motor) }},
+	{Key: "horsepower", Labels: specs.Labels{"en": "Horsepower", "ar": "القوة الحصانية"}, Group: specs.GroupEngine, Type: specs.TypeNumber, Unit: &specs.UnitHorsepower,
+		Value: func(s *variantSpecs) specs.Value { return specs.Required(s.Horsepower) }},
+	{Key: "transmission", Labels: specs.Labels{"en": "Transmission", "ar": "ناقل الحركة"}, Group: specs.GroupEngine, Type: specs.TypeEnum, Options: specs.TransmissionOptions,
+		Value: func(s *variantSpecs) specs.Value { return specs.Text(s.Transmission) }},
+	{Key: "gears", Labels: specs.Labels{"en": "Gears", "ar": "عدد السرعات"}, Group: specs.GroupEngine, Type: specs.TypeNumber,
+		Value: func(s *variantSpecs) specs.Value { return specs.Required(s.Speed) }},
+	{Key: "drivetrain", Labels: specs.Labels{"en": "Drivetrain", "ar": "نظام الدفع"}, Group: specs.GroupEngine, Type: specs.TypeEnum, Options: specs.DrivetrainOptions,
+		Value: func(s *variantSpecs) specs.Value { return specs.Text(s.TractionType) }},
+	{Key: "topSpeed", Labels: specs.Labels{"en": "Top speed", "ar": "السرعة القصوى"}, Group: specs.GroupPerformance, Type: specs.TypeNumber, Unit: &specs.UnitKilometerPerHour,
+		Value: func(s *variantSpecs) specs.Value { return specs.Required(s.MaxSpeed) }},
+	{Key: "acceleration", Labels: specs.Labels{"en": "0-100 km/h", "ar": "التسارع من 0 إلى 100 كم/س"}, Group: specs.GroupPerformance, Type: specs.TypeNumber, Unit: &specs.UnitSecond, Decimals: 1,
+		Value: func(s *variantSpecs) specs.Value { return specs.Float(s.Acceleration) }},
+	// LiterPerKM holds liters per 100 km, as consumption is quoted in Egypt
+	{Key: "fuelConsumption", Labels: specs.Labels{"en": "Fuel consumption", "ar": "استهلاك الوقود"}, Group: specs.GroupPerformance, Type: specs.TypeNumber, Unit: &specs.UnitLiterPer100Km, Decimals: 1,
+		Value: func(s *variantSpecs) specs.Value { return specs.Required(s.LiterPerKM) }},
+	{Key: "length", Labels: specs.Labels{"en": "Length", "ar": "الطول"}, Group: specs.GroupDimensions, Type: specs.TypeNumber, Unit: &specs.UnitMillimeter,
+		Value: func(s *variantSpecs) specs.Value { return specs.Int(s.LengthInMM) }},
+	{Key: "width", Labels: specs.Labels{"en": "Width", "ar": "العرض"}, Group: specs.GroupDimensions, Type: specs.TypeNumber, Unit: &specs.UnitMillimeter,
+		Value: func(s *variantSpecs) specs.Value { return specs.Int(s.WidthInMM) }},
+	{Key: "height", Labels: specs.Labels{"en": "Height", "ar": "الارتفاع"}, Group: specs.GroupDimensions, Type: specs.TypeNumber, Unit: &specs.UnitMillimeter,
+		Value: func(s *variantSpecs) specs.Value { return specs.Int(s.HeightInMM) }},
+	{Key: "wheelbase", Labels: specs.Labels{"en": "Wheelbase", "ar": "قاعدة العجلات"}, Group: specs.GroupDimensions, Type: specs.TypeNumber, Unit: &specs.UnitMillimeter,
+		Value: func(s *variantSpecs) specs.Value { return specs.Int(s.WheelBase) }},
+	{Key: "groundClearance", Labels: specs.Labels{"en": "Ground clearance", "ar": "الخلوص الأرضي"}, Group: specs.GroupDimensions, Type: specs.TypeNumber, Unit: &specs.UnitMillimeter,
+		Value: func(s *variantSpecs) specs.Value { return specs.Int(s.GroundClearanceInMM) }},
+	{Key: "seats", Labels: specs.Labels{"en": "Seats", "ar": "عدد المقاعد"}, Group: specs.GroupCapacity, Type: specs.TypeNumber,
+		Value: func(s *variantSpecs) specs.Value { return specs.Int(s.Seats) }},
+	{Key: "trunkCapacity", Labels: specs.Labels{"en": "Trunk capacity", "ar": "سعة الشنطة"}, Group: specs.GroupCapacity, Type: specs.TypeNumber, Unit: &specs.UnitLiter,
+		Value: func(s *variantSpecs) specs.Value { return specs.Int(s.TrunkSize) }},
+	{Key: "origin", Labels: specs.Labels{"en": "Origin", "ar": "بلد المنشأ"}, Group: specs.GroupOrigin, Type: specs.TypeText,
+		Value: func(s *variantSpecs) specs.Value { return specs.Text(s.Origin) }},
+	{Key: "assembledIn", Labels: specs.Labels{"en": "Assembled in", "ar": "بلد التجميع"}, Group: specs.GroupOrigin, Type: specs.TypeText,
+		Value: func(s *variantSpecs) specs.Value { return specs.Text(s.AssembledIn) }},
+}
@@ -0,0 +1,50 @@
+package cms
+
+// WebhookModelContentTypes maps Strapi model names (as sent in webhook payloads)
+// to the content types used in cache tags
+var WebhookModelContentTypes = map[string]string{
+	"advertisement":       "advertisement",
+	"application-version": "applicationVersion",
+	"brand":               "brand",
+	"car-model":           "carModel",
+	"car-variant":         "carVariant",
+	"city":                "city",
+	"governorate":         "governorate",
+	"home-card":           "homeCard",
+	"showroom":            "showroom",
+}
+
+// webhookSingleTypes are the models with a single entry, whose responses carry no documentId
+var webhookSingleTypes = map[string]bool{
+	"application-version": true,
+}
+
+// StrapiWebhookEvent is the payload of a Strapi 5 entry lifecycle webhook
+type StrapiWebhookEvent struct {
+	Event string `json:"event"` // entry.create, entry.update, entry.publish, entry.unpublish, entry.delete
+	Model string `json:"model"` // e.g. car-variant
+	UID   string `json:"uid"`   // e.g. api::car-variant.car-variant
+	Entry *struct {
+		DocumentID string `json:"documentId"`
+	} `json:"entry"`
+}
+
+// CacheTags returns the cache tags affected by the event, or nil if the model is not cached.
+// An update only invalidates the responses containing the entry. Creates, deletes and
+// (un)publishes also invalidate the whole content type, since list responses gain or lose
+// the entry without having contained it.
+func (e StrapiWebhookEvent) CacheTags() []string {
+	contentType, ok := WebhookModelContentTypes[e.Model]
+	if !ok {
+		return nil
+	}
+
+	if e.Entry == nil || e.Entry.DocumentID == "" || webhookSingleTypes[e.Model] {
+		return []string{ContentTypeTag(contentType)}
+	}
+	documentTag := DocumentTag(contentType, e.Entry.DocumentID)
+	if e.Event == "entry.update" {
+		return []string{documentTag}
+	}
+	return []string{ContentTypeTag(contentType), documentTag}
+}
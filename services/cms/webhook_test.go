@@ -0,0 +1,73 @@
+package cms
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestStrapiWebhookEventCacheTags(t *testing.T) {
+	tests := []struct {
+		name    string
+		payload string
+		want    []string
+	}{
+		{
+			"update only invalidates the entry",
+			`{"event":"entry.update","model":"car-variant","uid":"api::car-variant.car-variant","entry":{"id":7,"documentId":"abc123"}}`,
+			[]string{"carVariant:abc123"},
+		},
+		{
+			"create invalidates the content type",
+			`{"event":"entry.create","model":"brand","entry":{"documentId":"bmw"}}`,
+			[]string{"brand", "brand:bmw"},
+		},
+		{
+			"delete invalidates the content type",
+			`{"event":"entry.delete","model":"car-model","entry":{"documentId":"x5"}}`,
+			[]string{"carModel", "carModel:x5"},
+		},
+		{
+			"publish invalidates the content type",
+			`{"event":"entry.publish","model":"showroom","entry":{"documentId":"s1"}}`,
+			[]string{"showroom", "showroom:s1"},
+		},
+		{
+			"unpublish invalidates the content type",
+			`{"event":"entry.unpublish","model":"home-card","entry":{"documentId":"h1"}}`,
+			[]string{"homeCard", "homeCard:h1"},
+		},
+		{
+			"single types invalidate the content type",
+			`{"event":"entry.update","model":"application-version","entry":{"documentId":"v1"}}`,
+			[]string{"applicationVersion"},
+		},
+		{
+			"update without a documentId invalidates the content type",
+			`{"event":"entry.update","model":"city","entry":{"id":3}}`,
+			[]string{"city"},
+		},
+		{
+			"missing entry invalidates the content type",
+			`{"event":"entry.delete","model":"governorate"}`,
+			[]string{"governorate"},
+		},
+		{
+			"uncached models are ignored",
+			`{"event":"media.create","model":"file","entry":{"documentId":"f1"}}`,
+			nil,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var event StrapiWebhookEvent
+			if err := json.Unmarshal([]byte(test.payload), &event); err != nil {
+				t.Fatal(err)
+			}
+			if got := event.CacheTags(); !reflect.DeepEqual(got, test.want) {
+				t.Errorf("CacheTags() = %q, want %q", got, test.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,467 @@
+package main
+
+import (
+	"api-gateway/pkg/cache"
+	"api-gateway/pkg/imaging"
+	"api-gateway/services/cms/media"
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"regexp"
+	"runtime"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/goccy/go-json"
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/proxy"
+	"golang.org/x/sync/singleflight"
+)
+
+// transformWait is how long a request waits for a free transformation slot before failing with 503
+const transformWait = 10 * time.Second
+
+// maxUploadSourceBytes bounds the originals kept in the cache and downloaded for transformation;
+// larger files are streamed from Strapi
+const maxUploadSourceBytes = 25 << 20
+
+// Cache-Control values of /uploads responses
+const (
+	uploadCacheControlImmutable = "public, max-age=31536000, immutable" // hashed or versioned files
+	uploadCacheControlDefault   = "public, max-age=3600"
+	uploadCacheControlSigned    = "private, no-cache" // signed URLs must not be shared by caches
+)
+
+// hashedUploadName matches Strapi upload names, which end in a random hash (e.g. logo_d871d2a03a.png)
+var hashedUploadName = regexp.MustCompile(`_[0-9a-f]{10}\.[A-Za-z0-9]+$`)
+
+// errUploadTooLarge is returned for originals above maxUploadSourceBytes
+var errUploadTooLarge = errors.New("upload too large to cache")
+
+// uploadProxy serves Strapi uploads under /uploads. Originals up to maxUploadSourceBytes are
+// kept in the cache and revalidated with Strapi's ETag/Last-Modified once stale; larger files
+// and uncached range requests are proxied. Requests with width, height or quality parameters
+// get a resized copy; at most maxTransforms images are transformed at a time.
+type uploadProxy struct {
+	upstream   string             // Strapi base URL, e.g. http://localhost:1337
+	media      *media.URLStrategy // Verifies signed URLs
+	cache      cache.Cache        // Originals and transformed variants
+	cacheTTL   time.Duration
+	freshness  time.Duration
+	httpClient *http.Client
+	inflight   singleflight.Group // coalesces concurrent fetches and transformations
+	transforms chan struct{}      // semaphore bounding concurrent transformations
+}
+
+// uploadProxyConfig holds the settings of an uploadProxy
+type uploadProxyConfig struct {
+	Upstream      string
+	Media         *media.URLStrategy
+	Cache         cache.Cache   // Optional: originals and variants; nil fetches and transforms on every request
+	CacheTTL      time.Duration // How long entries are kept (default 7 days)
+	Freshness     time.Duration // How long originals are served before revalidation when Strapi sends no max-age (default 10 minutes)
+	MaxTransforms int           // Concurrent image transformations (default the number of CPUs)
+}
+
+// uploadEntry is a cached original or transformed variant. It is stored as its JSON metadata,
+// a newline and the body.
+type uploadEntry struct {
+	ContentType  string    `json:"contentType"`
+	ETag         string    `json:"etag"`
+	LastModified string    `json:"lastModified,omitempty"`
+	FreshUntil   time.Time `json:"freshUntil"`
+	SourceETag   string    `json:"sourceEtag,omitempty"` // Variants: ETag of the original they were made from
+	Body         []byte    `json:"-"`
+}
+
+// fresh reports whether the entry may be served without revalidation
+func (e *uploadEntry) fresh() bool {
+	return time.Now().Before(e.FreshUntil)
+}
+
+// newUploadProxy creates the /uploads handler
+func newUploadProxy(config uploadProxyConfig) *uploadProxy {
+	if config.Cache == nil {
+		config.Cache = &cache.NoOpCache{}
+	}
+	if config.CacheTTL == 0 {
+		config.CacheTTL = 7 * 24 * time.Hour
+	}
+	if config.Freshness == 0 {
+		config.Freshness = 10 * time.Minute
+	}
+	if config.MaxTransforms <= 0 {
+		config.MaxTransforms = runtime.NumCPU()
+	}
+	return &uploadProxy{
+		upstream:   strings.TrimSuffix(config.Upstream, "/"),
+		media:      config.Media,
+		cache:      config.Cache,
+		cacheTTL:   config.CacheTTL,
+		freshness:  config.Freshness,
+		httpClient: &http.Client{Timeout: 30 * time.Second},
+		transforms: make(chan struct{}, config.MaxTransforms),
+	}
+}
+
+// handle serves GET /uploads/*
+func (p *uploadProxy) handle(c *fiber.Ctx) error {
+	path, err := uploadPath(c.Params("*"))
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+	if err := p.media.VerifyRequest(path, c.Query("expires"), c.Query("sig")); err != nil {
+		return fiber.NewError(fiber.StatusForbidden, err.Error())
+	}
+
+	opts, err := imaging.ParseOptions(c.Query("width"), c.Query("height"), c.Query("fit"), c.Query("quality"))
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+	if opts.Resizes() || c.Query("quality") != "" {
+		// The output format depends on Accept, so shared caches must key variants on it
+		c.Vary(fiber.HeaderAccept)
+		acceptWebP := strings.Contains(c.Get(fiber.HeaderAccept), "image/webp")
+		entry, err := p.variant(c.UserContext(), path, opts, acceptWebP)
+		switch {
+		case err == nil:
+			return p.serve(c, path, entry)
+		case errors.Is(err, imaging.ErrUnsupported), errors.Is(err, errUploadTooLarge):
+			// Not an image we can transform (e.g. a PDF or SVG): serve the original
+		default:
+			return err
+		}
+	}
+
+	entry := p.lookup(c.UserContext(), originalCacheKey(path))
+	if entry == nil || !entry.fresh() {
+		if c.Get(fiber.HeaderRange) != "" {
+			// Don't hold a range request up downloading the whole file
+			return p.passthrough(c, path)
+		}
+		if entry, err = p.original(c.UserContext(), path); errors.Is(err, errUploadTooLarge) {
+			return p.passthrough(c, path)
+		} else if err != nil {
+			return err
+		}
+	}
+	return p.serve(c, path, entry)
+}
+
+// serve writes a cached entry, answering conditional and single-range requests
+func (p *uploadProxy) serve(c *fiber.Ctx, path string, entry *uploadEntry) error {
+	c.Set(fiber.HeaderContentType, entry.ContentType)
+	c.Set(fiber.HeaderETag, entry.ETag)
+	if entry.LastModified != "" {
+		c.Set(fiber.HeaderLastModified, entry.LastModified)
+	}
+	c.Set(fiber.HeaderCacheControl, p.cacheControl(c, path))
+	c.Set(fiber.HeaderAcceptRanges, "bytes")
+
+	if notModified(c, entry) {
+		return c.SendStatus(fiber.StatusNotModified)
+	}
+
+	// If-Range asks for the whole file when it changed since the client's partial copy
+	if c.Get(fiber.HeaderRange) != "" && (c.Get(fiber.HeaderIfRange) == "" || c.Get(fiber.HeaderIfRange) == entry.ETag) {
+		size := len(entry.Body)
+		ranges, err := c.Range(size)
+		if errors.Is(err, fiber.ErrRangeUnsatisfiable) {
+			c.Set(fiber.HeaderContentRange, fmt.Sprintf("bytes */%d", size))
+			return c.SendStatus(fiber.StatusRequestedRangeNotSatisfiable)
+		}
+		// Multiple ranges are answered with the whole file
+		if err == nil && ranges.Type == "bytes" && len(ranges.Ranges) == 1 {
+			r := ranges.Ranges[0]
+			c.Set(fiber.HeaderContentRange, fmt.Sprintf("bytes %d-%d/%d", r.Start, r.End, size))
+			return c.Status(fiber.StatusPartialContent).Send(entry.Body[r.Start : r.End+1])
+		}
+	}
+
+	return c.Send(entry.Body)
+}
+
+// passthrough proxies a request to Strapi unchanged, including Range and conditional headers
+func (p *uploadProxy) passthrough(c *fiber.Ctx, path string) error {
+	if err := proxy.Do(c, p.upstreamURL(path)); err != nil {
+		return err
+	}
+	if status := c.Response().StatusCode(); status == fiber.StatusOK || status == fiber.StatusPartialContent || status == fiber.StatusNotModified {
+		c.Set(fiber.HeaderCacheControl, p.cacheControl(c, path))
+	}
+	return nil
+}
+
+// cacheControl picks the Cache-Control header for an upload: hashed Strapi names and
+// cache-busted URLs never change, signed URLs are private
+func (p *uploadProxy) cacheControl(c *fiber.Ctx, path string) string {
+	switch {
+	case c.Query("sig") != "":
+		return uploadCacheControlSigned
+	case hashedUploadName.MatchString(path), c.Query("v") != "":
+		return uploadCacheControlImmutable
+	default:
+		return uploadCacheControlDefault
+	}
+}
+
+// original returns an upload from the cache, fetching or revalidating it with Strapi when stale
+func (p *uploadProxy) original(ctx context.Context, path string) (*uploadEntry, error) {
+	key := originalCacheKey(path)
+	cached := p.lookup(ctx, key)
+	if cached != nil && cached.fresh() {
+		return cached, nil
+	}
+
+	value, err, _ := p.inflight.Do(key, func() (interface{}, error) {
+		return p.fetch(context.WithoutCancel(ctx), path, cached)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return value.(*uploadEntry), nil
+}
+
+// fetch downloads an original from Strapi, or revalidates the stale copy if given
+func (p *uploadProxy) fetch(ctx context.Context, path string, stale *uploadEntry) (*uploadEntry, error) {
+	key := originalCacheKey(path)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, p.upstreamURL(path), nil)
+	if err != nil {
+		return nil, err
+	}
+	if stale != nil {
+		req.Header.Set(fiber.HeaderIfNoneMatch, stale.ETag)
+		if stale.LastModified != "" {
+			req.Header.Set(fiber.HeaderIfModifiedSince, stale.LastModified)
+		}
+	}
+
+	resp, err := p.httpClient.Do(req)
+	if err != nil {
+		return nil, fiber.NewError(fiber.StatusBadGateway, "Failed to fetch upload")
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusNotModified && stale != nil:
+		stale.FreshUntil = p.freshUntil(resp.Header)
+		p.store(ctx, key, stale)
+		return stale, nil
+	case resp.StatusCode == http.StatusNotFound:
+		_ = p.cache.Delete(ctx, key)
+		return nil, fiber.ErrNotFound
+	case resp.StatusCode != http.StatusOK:
+		return nil, fiber.NewError(fiber.StatusBadGateway, fmt.Sprintf("Upload responded with status %d", resp.StatusCode))
+	case resp.ContentLength > maxUploadSourceBytes:
+		return nil, errUploadTooLarge
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxUploadSourceBytes+1))
+	if err != nil {
+		return nil, fiber.NewError(fiber.StatusBadGateway, "Failed to read upload")
+	}
+	if len(body) > maxUploadSourceBytes {
+		return nil, errUploadTooLarge
+	}
+
+	entry := &uploadEntry{
+		ContentType:  resp.Header.Get(fiber.HeaderContentType),
+		ETag:         resp.Header.Get(fiber.HeaderETag),
+		LastModified: resp.Header.Get(fiber.HeaderLastModified),
+		FreshUntil:   p.freshUntil(resp.Header),
+		Body:         body,
+	}
+	if entry.ETag == "" {
+		entry.ETag = bodyETag(body)
+	}
+	if entry.ContentType == "" {
+		entry.ContentType = http.DetectContentType(body)
+	}
+	if !strings.Contains(resp.Header.Get(fiber.HeaderCacheControl), "no-store") {
+		p.store(ctx, key, entry)
+	}
+	return entry, nil
+}
+
+// freshUntil derives how long an original is fresh from Strapi's Cache-Control max-age
+func (p *uploadProxy) freshUntil(header http.Header) time.Time {
+	for _, directive := range strings.Split(header.Get(fiber.HeaderCacheControl), ",") {
+		directive = strings.TrimSpace(directive)
+		if directive == "no-cache" {
+			return time.Time{}
+		}
+		if value, ok := strings.CutPrefix(directive, "max-age="); ok {
+			if seconds, err := strconv.Atoi(value); err == nil {
+				return time.Now().Add(time.Duration(seconds) * time.Second)
+			}
+		}
+	}
+	return time.Now().Add(p.freshness)
+}
+
+// variant returns a transformed upload from the cache, or transforms and caches it. A stale
+// variant is kept if its original did not change. Variants are cached per output format:
+// JPEG variants with the quality, lossless ones (WebP when acceptWebP is set, PNG otherwise)
+// without it.
+func (p *uploadProxy) variant(ctx context.Context, path string, opts imaging.Options, acceptWebP bool) (*uploadEntry, error) {
+	losslessFormat := "png"
+	if acceptWebP {
+		losslessFormat = "webp"
+	}
+	key := variantCacheKey(path, opts, "jpeg")
+	losslessKey := variantCacheKey(path, opts.Lossless(), losslessFormat)
+	cached := p.lookup(ctx, key)
+	if cached == nil {
+		cached = p.lookup(ctx, losslessKey)
+	}
+	if cached != nil && cached.fresh() {
+		return cached, nil
+	}
+
+	// Requests share a transformation only if they get the same output for any source
+	flightKey := variantCacheKey(path, opts, losslessFormat)
+	value, err, _ := p.inflight.Do(flightKey, func() (interface{}, error) {
+		ctx := context.WithoutCancel(ctx)
+		source, err := p.original(ctx, path)
+		if err != nil {
+			return nil, err
+		}
+		storeKey := key
+		if cached != nil && cached.SourceETag == source.ETag {
+			if cached.ContentType != "image/jpeg" {
+				storeKey = losslessKey
+			}
+			cached.FreshUntil = source.FreshUntil
+			p.store(ctx, storeKey, cached)
+			return cached, nil
+		}
+
+		select {
+		case p.transforms <- struct{}{}:
+			defer func() { <-p.transforms }()
+		case <-time.After(transformWait):
+			return nil, fiber.NewError(fiber.StatusServiceUnavailable, "Too many images being resized, try again later")
+		}
+		result, err := imaging.Transform(source.Body, opts, acceptWebP)
+		if err != nil {
+			return nil, err
+		}
+		if result.Lossless {
+			// Drop a JPEG variant made before the original gained transparency
+			storeKey = losslessKey
+			_ = p.cache.Delete(ctx, key)
+		}
+		entry := &uploadEntry{
+			ContentType:  result.ContentType,
+			ETag:         bodyETag(result.Data),
+			LastModified: source.LastModified,
+			FreshUntil:   source.FreshUntil,
+			SourceETag:   source.ETag,
+			Body:         result.Data,
+		}
+		p.store(ctx, storeKey, entry)
+		return entry, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return value.(*uploadEntry), nil
+}
+
+// lookup reads an entry from the cache; errors and undecodable entries are misses
+func (p *uploadProxy) lookup(ctx context.Context, key string) *uploadEntry {
+	data, err := p.cache.Get(ctx, key)
+	if err != nil || data == nil {
+		return nil
+	}
+	meta, body, ok := bytes.Cut(data, []byte{'\n'})
+	if !ok {
+		return nil
+	}
+	var entry uploadEntry
+	if err := json.Unmarshal(meta, &entry); err != nil {
+		return nil
+	}
+	entry.Body = body
+	return &entry
+}
+
+// store writes an entry to the cache
+func (p *uploadProxy) store(ctx context.Context, key string, entry *uploadEntry) {
+	meta, err := json.Marshal(entry)
+	if err != nil {
+		return
+	}
+	data := make([]byte, 0, len(meta)+1+len(entry.Body))
+	data = append(append(append(data, meta...), '\n'), entry.Body...)
+	if err := p.cache.Set(ctx, key, data, p.cacheTTL); err != nil {
+		log.Printf("Failed to cache upload %s: %v", key, err)
+	}
+}
+
+// upstreamURL is the Strapi URL of an upload path
+func (p *uploadProxy) upstreamURL(path string) string {
+	return p.upstream + (&url.URL{Path: path}).EscapedPath()
+}
+
+// uploadPath validates the wildcard part of an /uploads URL, which may span several segments,
+// and returns the upload path. Empty, "." and ".." segments are rejected so requests cannot
+// escape /uploads on Strapi.
+func uploadPath(param string) (string, error) {
+	decoded, err := url.PathUnescape(param)
+	if err != nil || decoded == "" || strings.ContainsAny(decoded, "\\\x00") {
+		return "", errors.New("invalid upload path")
+	}
+	for _, segment := range strings.Split(decoded, "/") {
+		if segment == "" || segment == "." || segment == ".." {
+			return "", errors.New("invalid upload path")
+		}
+	}
+	return "/uploads/" + decoded, nil
+}
+
+// originalCacheKey is the cache key of an original upload
+func originalCacheKey(path string) string {
+	return "original|" + path
+}
+
+// variantCacheKey is the cache key of a transformed upload in an output format (jpeg, png or webp)
+func variantCacheKey(path string, opts imaging.Options, format string) string {
+	return "variant|" + path + "|" + opts.Key() + "|" + format
+}
+
+// notModified evaluates If-None-Match, or If-Modified-Since without it, against an entry
+func notModified(c *fiber.Ctx, entry *uploadEntry) bool {
+	if ifNoneMatch := c.Get(fiber.HeaderIfNoneMatch); ifNoneMatch != "" {
+		for _, tag := range strings.Split(ifNoneMatch, ",") {
+			tag = strings.TrimPrefix(strings.TrimSpace(tag), "W/")
+			if tag == "*" || tag == strings.TrimPrefix(entry.ETag, "W/") {
+				return true
+			}
+		}
+		return false
+	}
+	if ifModifiedSince := c.Get(fiber.HeaderIfModifiedSince); ifModifiedSince != "" && entry.LastModified != "" {
+		since, err := http.ParseTime(ifModifiedSince)
+		if err != nil {
+			return false
+		}
+		modified, err := http.ParseTime(entry.LastModified)
+		return err == nil && !modified.After(since)
+	}
+	return false
+}
+
+// bodyETag derives a strong ETag from content
+func bodyETag(body []byte) string {
+	sum := sha256.Sum256(body)
+	return `"` + hex.EncodeToString(sum[:16]) + `"`
+}
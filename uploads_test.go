@@ -0,0 +1,270 @@
+package main
+
+import (
+	"api-gateway/pkg/cache"
+	"api-gateway/pkg/imaging"
+	"api-gateway/services/cms/media"
+	"bytes"
+	"context"
+	"image"
+	"image/png"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// newUploadsApp serves an uploadProxy in front of a stub Strapi, caching in memory. The
+// returned counter counts the requests that reached Strapi.
+func newUploadsApp(t *testing.T, handler http.HandlerFunc) (*fiber.App, *cache.MemoryCache, *atomic.Int64) {
+	t.Helper()
+	var upstreamRequests atomic.Int64
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		upstreamRequests.Add(1)
+		handler(w, r)
+	}))
+	t.Cleanup(upstream.Close)
+
+	memoryCache := cache.NewMemoryCache(cache.MemoryCacheConfig{})
+	uploads := newUploadProxy(uploadProxyConfig{
+		Upstream: upstream.URL,
+		Media:    &media.URLStrategy{},
+		Cache:    memoryCache,
+	})
+	app := fiber.New(fiber.Config{ErrorHandler: errorHandler})
+	app.Get("/uploads/*", uploads.handle)
+	return app, memoryCache, &upstreamRequests
+}
+
+// uploadLastModified is the Last-Modified of files served by serveBytes
+const uploadLastModified = "Wed, 01 Jan 2025 00:00:00 GMT"
+
+// serveBytes answers every request with body as contentType, with an ETag and Last-Modified
+func serveBytes(contentType string, body []byte) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", contentType)
+		w.Header().Set("ETag", `"v1"`)
+		w.Header().Set("Last-Modified", uploadLastModified)
+		w.Write(body)
+	}
+}
+
+// transparentPNG returns a w x h half-transparent PNG
+func transparentPNG(t *testing.T, w, h int) []byte {
+	t.Helper()
+	img := image.NewNRGBA(image.Rect(0, 0, w, h))
+	for i := 0; i < len(img.Pix); i += 4 {
+		copy(img.Pix[i:], []byte{255, 0, 0, 128})
+	}
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestUploadVariantNegotiatesWebP(t *testing.T) {
+	app, memoryCache, _ := newUploadsApp(t, serveBytes("image/png", transparentPNG(t, 400, 200)))
+
+	tests := []struct {
+		name     string
+		accept   string
+		wantType string
+		format   string
+	}{
+		{"WebP client", "image/avif,image/webp,*/*", "image/webp", "webp"},
+		{"other client", "image/*", "image/png", "png"},
+		{"no Accept", "", "image/png", "png"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(fiber.MethodGet, "/uploads/logo.png?width=128", nil)
+			if tt.accept != "" {
+				req.Header.Set(fiber.HeaderAccept, tt.accept)
+			}
+			resp, err := app.Test(req)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if resp.StatusCode != fiber.StatusOK {
+				t.Fatalf("status = %d, want 200", resp.StatusCode)
+			}
+			if got := resp.Header.Get(fiber.HeaderContentType); got != tt.wantType {
+				t.Errorf("Content-Type = %q, want %q", got, tt.wantType)
+			}
+			if got := resp.Header.Get(fiber.HeaderVary); got != fiber.HeaderAccept {
+				t.Errorf("Vary = %q, want Accept", got)
+			}
+
+			opts := imaging.Options{Width: 128, Fit: imaging.FitContain}
+			if cached, _ := memoryCache.Get(context.Background(), variantCacheKey("/uploads/logo.png", opts, tt.format)); cached == nil {
+				t.Errorf("no %s variant cached", tt.format)
+			}
+		})
+	}
+}
+
+func TestUploadVariantVaryOnUntransformableFiles(t *testing.T) {
+	app, _, _ := newUploadsApp(t, serveBytes("application/pdf", []byte("%PDF-1.7")))
+
+	resp, err := app.Test(httptest.NewRequest(fiber.MethodGet, "/uploads/brochure.pdf?width=128", nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.StatusCode != fiber.StatusOK || resp.Header.Get(fiber.HeaderContentType) != "application/pdf" {
+		t.Errorf("got %d %s, want the original PDF", resp.StatusCode, resp.Header.Get(fiber.HeaderContentType))
+	}
+	if got := resp.Header.Get(fiber.HeaderVary); got != fiber.HeaderAccept {
+		t.Errorf("Vary = %q, want Accept", got)
+	}
+}
+
+func TestUploadPath(t *testing.T) {
+	tests := []struct {
+		param   string
+		want    string
+		wantErr bool
+	}{
+		{param: "logo.png", want: "/uploads/logo.png"},
+		{param: "brands/2024/logo.png", want: "/uploads/brands/2024/logo.png"},
+		{param: "car%20photo.jpg", want: "/uploads/car photo.jpg"},
+		{param: "", wantErr: true},
+		{param: "../secret", wantErr: true},
+		{param: "brands/../../admin", wantErr: true},
+		{param: "%2e%2e/secret", wantErr: true},
+		{param: "brands/%2E%2E/%2e%2E/admin", wantErr: true},
+		{param: "./logo.png", wantErr: true},
+		{param: "brands//logo.png", wantErr: true},
+		{param: "logo.png/", wantErr: true},
+		{param: "..%5csecret", wantErr: true},
+		{param: "logo.png%00.jpg", wantErr: true},
+		{param: "%zz", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.param, func(t *testing.T) {
+			got, err := uploadPath(tt.param)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("uploadPath(%q) = %q, want an error", tt.param, got)
+				}
+				return
+			}
+			if err != nil || got != tt.want {
+				t.Errorf("uploadPath(%q) = %q, %v, want %q", tt.param, got, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestUploadRejectsTraversal(t *testing.T) {
+	app, _, upstreamRequests := newUploadsApp(t, serveBytes("text/plain", []byte("secret")))
+
+	resp, err := app.Test(httptest.NewRequest(fiber.MethodGet, "/uploads/brands/%2e%2e/%2e%2e/admin", nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.StatusCode != fiber.StatusBadRequest {
+		t.Errorf("status = %d, want 400", resp.StatusCode)
+	}
+	if n := upstreamRequests.Load(); n != 0 {
+		t.Errorf("%d requests reached Strapi, want none", n)
+	}
+}
+
+func TestUploadConditionalAndRangeRequests(t *testing.T) {
+	app, _, upstreamRequests := newUploadsApp(t, serveBytes("text/plain", []byte("0123456789")))
+
+	// Cache the original
+	first, err := app.Test(httptest.NewRequest(fiber.MethodGet, "/uploads/notes.txt", nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if first.StatusCode != fiber.StatusOK || first.Header.Get(fiber.HeaderETag) != `"v1"` {
+		t.Fatalf("first response = %d with ETag %q, want 200 with Strapi's ETag", first.StatusCode, first.Header.Get(fiber.HeaderETag))
+	}
+
+	tests := []struct {
+		name             string
+		headers          map[string]string
+		wantStatus       int
+		wantBody         string
+		wantContentRange string
+	}{
+		{"matching If-None-Match", map[string]string{"If-None-Match": `"v1"`}, fiber.StatusNotModified, "", ""},
+		{"weak If-None-Match", map[string]string{"If-None-Match": `"v0", W/"v1"`}, fiber.StatusNotModified, "", ""},
+		{"changed If-None-Match", map[string]string{"If-None-Match": `"v0"`}, fiber.StatusOK, "0123456789", ""},
+		{"If-Modified-Since", map[string]string{"If-Modified-Since": uploadLastModified}, fiber.StatusNotModified, "", ""},
+		{"older If-Modified-Since", map[string]string{"If-Modified-Since": "Tue, 31 Dec 2024 00:00:00 GMT"}, fiber.StatusOK, "0123456789", ""},
+		{"If-None-Match wins over If-Modified-Since", map[string]string{"If-None-Match": `"v0"`, "If-Modified-Since": uploadLastModified}, fiber.StatusOK, "0123456789", ""},
+		{"single range", map[string]string{"Range": "bytes=2-5"}, fiber.StatusPartialContent, "2345", "bytes 2-5/10"},
+		{"suffix range", map[string]string{"Range": "bytes=-3"}, fiber.StatusPartialContent, "789", "bytes 7-9/10"},
+		{"multiple ranges", map[string]string{"Range": "bytes=0-1,4-5"}, fiber.StatusOK, "0123456789", ""},
+		{"unsatisfiable range", map[string]string{"Range": "bytes=20-30"}, fiber.StatusRequestedRangeNotSatisfiable, "", "bytes */10"},
+		{"matching If-Range", map[string]string{"Range": "bytes=0-2", "If-Range": `"v1"`}, fiber.StatusPartialContent, "012", "bytes 0-2/10"},
+		{"changed If-Range", map[string]string{"Range": "bytes=0-2", "If-Range": `"v0"`}, fiber.StatusOK, "0123456789", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(fiber.MethodGet, "/uploads/notes.txt", nil)
+			for name, value := range tt.headers {
+				req.Header.Set(name, value)
+			}
+			resp, err := app.Test(req)
+			if err != nil {
+				t.Fatal(err)
+			}
+			body, _ := io.ReadAll(resp.Body)
+
+			if resp.StatusCode != tt.wantStatus {
+				t.Errorf("status = %d, want %d", resp.StatusCode, tt.wantStatus)
+			}
+			if tt.wantStatus != fiber.StatusRequestedRangeNotSatisfiable && string(body) != tt.wantBody {
+				t.Errorf("body = %q, want %q", body, tt.wantBody)
+			}
+			if got := resp.Header.Get(fiber.HeaderContentRange); got != tt.wantContentRange {
+				t.Errorf("Content-Range = %q, want %q", got, tt.wantContentRange)
+			}
+			if got := resp.Header.Get(fiber.HeaderETag); got != `"v1"` {
+				t.Errorf("ETag = %q, want %q", got, `"v1"`)
+			}
+		})
+	}
+
+	if n := upstreamRequests.Load(); n != 1 {
+		t.Errorf("%d requests reached Strapi, want only the first", n)
+	}
+}
+
+func TestUploadRangeOfUncachedFileIsProxied(t *testing.T) {
+	var gotRange string
+	app, _, _ := newUploadsApp(t, func(w http.ResponseWriter, r *http.Request) {
+		gotRange = r.Header.Get("Range")
+		w.Header().Set("Content-Range", "bytes 0-2/10")
+		w.WriteHeader(http.StatusPartialContent)
+		w.Write([]byte("012"))
+	})
+
+	req := httptest.NewRequest(fiber.MethodGet, "/uploads/video_0123456789.mp4", nil)
+	req.Header.Set(fiber.HeaderRange, "bytes=0-2")
+	resp, err := app.Test(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+
+	if gotRange != "bytes=0-2" {
+		t.Errorf("Strapi got Range %q, want bytes=0-2", gotRange)
+	}
+	if resp.StatusCode != fiber.StatusPartialContent || string(body) != "012" {
+		t.Errorf("got %d %q, want 206 012", resp.StatusCode, body)
+	}
+	if got := resp.Header.Get(fiber.HeaderCacheControl); got != uploadCacheControlImmutable {
+		t.Errorf("Cache-Control = %q, want %q for a hashed upload name", got, uploadCacheControlImmutable)
+	}
+}
@@ -0,0 +1,47 @@
+package main
+
+import (
+	"api-gateway/services/cms"
+	_ "embed"
+	"fmt"
+	"os"
+)
+
+// cmsSchema is the Strapi GraphQL schema the CMS operations are validated against
+//
+//go:embed schema.gql
+var cmsSchema string
+
+// validateQueries implements the validate-queries subcommand. It validates the registered
+// CMS operations against the embedded schema.gql, or the schema file given as argument
+// (e.g. a fresh export from Strapi), and returns the process exit code. Unverified
+// operations are listed but not validated.
+func validateQueries(args []string) int {
+	schema := cmsSchema
+	if len(args) > 0 {
+		schemaBytes, err := os.ReadFile(args[0])
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to read schema: %v\n", err)
+			return 2
+		}
+		schema = string(schemaBytes)
+	}
+
+	if err := cms.ValidateOperations(schema); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+
+	valid, unverified := 0, 0
+	for _, op := range cms.Operations() {
+		if op.Unverified {
+			fmt.Printf("%-28s %s (unverified, not validated)\n", op.Name, op.Hash)
+			unverified++
+			continue
+		}
+		fmt.Printf("%-28s %s\n", op.Name, op.Hash)
+		valid++
+	}
+	fmt.Printf("%d operations valid, %d unverified\n", valid, unverified)
+	return 0
+}
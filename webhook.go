@@ -0,0 +1,60 @@
+package main
+
+import (
+	"api-gateway/services/cms"
+	"crypto/subtle"
+	"log"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// strapiWebhook invalidates the cache entries affected by Strapi lifecycle webhooks
+type strapiWebhook struct {
+	cms    *cms.CMSClient
+	secret string // Shared secret expected in X-Webhook-Secret; empty rejects every request
+}
+
+// handle serves POST /api/webhooks/strapi
+func (w *strapiWebhook) handle(c *fiber.Ctx) error {
+	secret := c.Get("X-Webhook-Secret")
+	if w.secret == "" || subtle.ConstantTimeCompare([]byte(secret), []byte(w.secret)) != 1 {
+		return fiber.NewError(fiber.StatusUnauthorized, "Unauthorized: Invalid or missing webhook secret")
+	}
+
+	var event cms.StrapiWebhookEvent
+	if err := c.BodyParser(&event); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid webhook payload")
+	}
+
+	tags := event.CacheTags()
+	if len(tags) == 0 {
+		// Not a cached model (e.g. media or users) - nothing to do
+		return c.JSON(fiber.Map{
+			"success": true,
+			"event":   event.Event,
+			"model":   event.Model,
+			"tags":    []string{},
+		})
+	}
+
+	ctx := c.UserContext()
+	var removed int
+	var err error
+	if c.QueryBool("rewarm") {
+		removed, err = w.cms.InvalidateTagsAndRewarm(ctx, tags...)
+	} else {
+		removed, err = w.cms.InvalidateTags(ctx, tags...)
+	}
+	if err != nil {
+		return err
+	}
+
+	log.Printf("Strapi webhook %s %s: invalidated %d entries for tags %v", event.Event, event.Model, removed, tags)
+	return c.JSON(fiber.Map{
+		"success": true,
+		"event":   event.Event,
+		"model":   event.Model,
+		"tags":    tags,
+		"removed": removed,
+	})
+}
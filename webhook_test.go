@@ -0,0 +1,126 @@
+package main
+
+import (
+	"api-gateway/pkg/cache"
+	"api-gateway/services/cms"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/gofiber/fiber/v2"
+	"github.com/redis/go-redis/v9"
+)
+
+// newWebhookApp serves a strapiWebhook whose CMS client caches in an in-process Redis
+func newWebhookApp(t *testing.T, secret string) (*fiber.App, *cache.RedisCache) {
+	t.Helper()
+	server := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: server.Addr()})
+	t.Cleanup(func() { client.Close() })
+	redisCache := cache.NewRedisCache(client, "cms:")
+
+	webhook := &strapiWebhook{
+		cms:    cms.NewCMSClient(cms.Config{BaseURL: "http://cms.invalid/graphql", Cache: redisCache}),
+		secret: secret,
+	}
+	app := fiber.New(fiber.Config{ErrorHandler: errorHandler})
+	app.Post("/api/webhooks/strapi", webhook.handle)
+	return app, redisCache
+}
+
+func TestStrapiWebhookRejectsWrongSecrets(t *testing.T) {
+	tests := []struct {
+		name       string
+		configured string
+		sent       string
+	}{
+		{"missing", "s3cret-value", ""},
+		{"wrong", "s3cret-value", "s3cret-valuf"},
+		{"prefix", "s3cret-value", "s3cret"},
+		{"longer", "s3cret-value", "s3cret-value2"},
+		{"not configured", "", ""},
+		{"not configured with a header", "", "anything"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			app, _ := newWebhookApp(t, test.configured)
+			req := httptest.NewRequest("POST", "/api/webhooks/strapi", strings.NewReader(`{"event":"entry.update","model":"brand","entry":{"documentId":"bmw"}}`))
+			req.Header.Set("Content-Type", "application/json")
+			if test.sent != "" {
+				req.Header.Set("X-Webhook-Secret", test.sent)
+			}
+
+			resp, err := app.Test(req)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if resp.StatusCode != fiber.StatusUnauthorized {
+				t.Errorf("status = %d, want 401", resp.StatusCode)
+			}
+		})
+	}
+}
+
+func TestStrapiWebhookInvalidatesTaggedEntries(t *testing.T) {
+	ctx := context.Background()
+	app, redisCache := newWebhookApp(t, "s3cret-value")
+	redisCache.SetWithTags(ctx, "graphql:brand-page", []byte("{}"), time.Hour, []string{"brand", "brand:bmw"})
+	redisCache.SetWithTags(ctx, "graphql:brand-list", []byte("{}"), time.Hour, []string{"brand", "brand:bmw", "brand:audi"})
+	redisCache.SetWithTags(ctx, "graphql:other-brand", []byte("{}"), time.Hour, []string{"brand", "brand:audi"})
+
+	tests := []struct {
+		name        string
+		payload     string
+		wantStatus  int
+		wantTags    []string
+		wantRemoved float64
+		wantKept    []string
+	}{
+		{"update evicts only responses with the entry", `{"event":"entry.update","model":"brand","entry":{"documentId":"bmw"}}`, 200, []string{"brand:bmw"}, 2, []string{"graphql:other-brand"}},
+		{"uncached models are acknowledged", `{"event":"entry.update","model":"file","entry":{"documentId":"f1"}}`, 200, []string{}, 0, []string{"graphql:other-brand"}},
+		{"create evicts the content type", `{"event":"entry.create","model":"brand","entry":{"documentId":"kia"}}`, 200, []string{"brand", "brand:kia"}, 1, nil},
+		{"invalid payload", `{"event":`, 400, nil, 0, nil},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			req := httptest.NewRequest("POST", "/api/webhooks/strapi", strings.NewReader(test.payload))
+			req.Header.Set("Content-Type", "application/json")
+			req.Header.Set("X-Webhook-Secret", "s3cret-value")
+			resp, err := app.Test(req)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if resp.StatusCode != test.wantStatus {
+				t.Fatalf("status = %d, want %d", resp.StatusCode, test.wantStatus)
+			}
+			if test.wantStatus != 200 {
+				return
+			}
+
+			raw, _ := io.ReadAll(resp.Body)
+			var body struct {
+				Tags    []string `json:"tags"`
+				Removed float64  `json:"removed"`
+			}
+			if err := json.Unmarshal(raw, &body); err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(body.Tags, test.wantTags) || body.Removed != test.wantRemoved {
+				t.Errorf("response %s, want tags %q and %v removed", raw, test.wantTags, test.wantRemoved)
+			}
+			for _, key := range test.wantKept {
+				if exists, _ := redisCache.Exists(ctx, key); !exists {
+					t.Errorf("%s was evicted", key)
+				}
+			}
+		})
+	}
+}